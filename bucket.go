@@ -0,0 +1,186 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+/*================================*\
+	桶管理接口
+\*================================*/
+
+// BucketAdmin 桶级别的管理接口, bucket由参数指定, 用于按租户创建桶等环境初始化
+type BucketAdmin interface {
+	CreateBucket(ctx context.Context, bucket string, acl string) error
+	HeadBucket(ctx context.Context, bucket string) error
+	HasBucket(ctx context.Context, bucket string) (bool, error)
+	DeleteBucket(ctx context.Context, bucket string) error
+	GetBucketLocation(ctx context.Context, bucket string) (string, error)
+	PutBucketLifecycle(ctx context.Context, bucket string, config *LifecycleConfiguration) error
+	GetBucketLifecycle(ctx context.Context, bucket string) (*LifecycleConfiguration, error)
+	DeleteBucketLifecycle(ctx context.Context, bucket string) error
+	PutBucketCors(ctx context.Context, bucket string, config *CORSConfiguration) error
+	GetBucketCors(ctx context.Context, bucket string) (*CORSConfiguration, error)
+	DeleteBucketCors(ctx context.Context, bucket string) error
+	EnableBucketVersioning(ctx context.Context, bucket string) error
+	SuspendBucketVersioning(ctx context.Context, bucket string) error
+	GetBucketVersioning(ctx context.Context, bucket string) (string, error)
+}
+
+// 常用的ACL
+const (
+	ACLPrivate         = "private"
+	ACLPublicRead      = "public-read"
+	ACLPublicReadWrite = "public-read-write"
+)
+
+type bucketAdminImpl struct {
+	*ossiImpl
+	region string
+}
+
+// NewBucketAdmin 创建桶管理接口, 配置同New. 新建的桶位于StorageConfig.Region
+func NewBucketAdmin(use string, config *Config) (BucketAdmin, error) {
+	o, err := New(use, config)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := o.(*ossiImpl).storage.(BucketStorage); !ok {
+		return nil, fmt.Errorf("bucket admin: signature %q not supported", config.Signature)
+	}
+	return &bucketAdminImpl{ossiImpl: o.(*ossiImpl), region: config.Region}, nil
+}
+
+/*
+CreateBucket 创建桶, acl为空时使用云厂默认权限(私有). 亚马逊的us-east-1不能指定LocationConstraint
+*/
+func (b *bucketAdminImpl) CreateBucket(ctx context.Context, bucket string, acl string) error {
+	var body []byte
+	if b.region != "" && b.region != "us-east-1" {
+		body, _ = xml.Marshal(&createBucketConfiguration{LocationConstraint: b.region})
+	}
+	rsp, err := b.do(ctx, &request{
+		setting:       func(s Storage) *RequestSetting { return s.(BucketStorage).CreateBucket(bucket, acl) },
+		body:          bytes.NewReader(body),
+		contentLength: int64(len(body)),
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+// HeadBucket 桶不存在时返回ErrNotFound, 无权限时返回ErrAccessDenied
+func (b *bucketAdminImpl) HeadBucket(ctx context.Context, bucket string) error {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).HeadBucket(bucket) },
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+func (b *bucketAdminImpl) HasBucket(ctx context.Context, bucket string) (bool, error) {
+	err := b.HeadBucket(ctx, bucket)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, ErrNotFound) {
+		return false, nil
+	}
+	return false, err
+}
+
+// DeleteBucket 删除桶, 桶必须为空
+func (b *bucketAdminImpl) DeleteBucket(ctx context.Context, bucket string) error {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).DeleteBucket(bucket) },
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+// GetBucketLocation 返回桶所在区域. 注意: 亚马逊us-east-1返回空
+func (b *bucketAdminImpl) GetBucketLocation(ctx context.Context, bucket string) (string, error) {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).GetBucketLocation(bucket) },
+	})
+	if err != nil {
+		return "", err
+	}
+	defer discardResponseBody(rsp)
+
+	return ExtractBucketLocation(rsp)
+}
+
+// putBucketContent 提交桶配置(XML), 附带服务端要求的Content-MD5
+func (b *bucketAdminImpl) putBucketContent(ctx context.Context, body []byte, setting func(s BucketStorage, contentMD5 string) *RequestSetting) error {
+	contentMD5 := base64.StdEncoding.EncodeToString(Md5(body))
+	rsp, err := b.do(ctx, &request{
+		setting:       func(s Storage) *RequestSetting { return setting(s.(BucketStorage), contentMD5) },
+		body:          bytes.NewReader(body),
+		contentLength: int64(len(body)),
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+/****************************************
+ * bucket 辅助数据结构
+ ****************************************/
+
+type createBucketConfiguration struct {
+	XMLName            xml.Name `xml:"CreateBucketConfiguration"`
+	LocationConstraint string   `xml:"LocationConstraint"`
+}
+
+// bucketLocation 兼容<LocationConstraint>region</LocationConstraint>及嵌套LocationConstraint的格式
+type bucketLocation struct {
+	Location string `xml:",chardata"`
+	Nested   string `xml:"LocationConstraint"`
+}
+
+func ExtractBucketLocation(rsp *http.Response) (string, error) {
+	result := new(bucketLocation)
+	if err := xml.NewDecoder(rsp.Body).Decode(result); err != nil {
+		return "", err
+	}
+	if result.Nested != "" {
+		return result.Nested, nil
+	}
+	return strings.TrimSpace(result.Location), nil
+}
+
+// bucketName 请求的bucket: 桶操作由ctx.Bucket指定, 否则为StorageConfig.Bucket
+func bucketName(ctx *ProviderContext, c *StorageConfig) string {
+	if ctx.Bucket != "" {
+		return ctx.Bucket
+	}
+	return c.Bucket
+}
+
+// bucketDomain 请求的访问域名. 虚拟主机方式访问其他bucket时, 域名为"<bucket>.<Endpoint>"
+func bucketDomain(ctx *ProviderContext, p *Profile, c *StorageConfig) string {
+	if ctx.Bucket == "" || ctx.Bucket == c.Bucket || p.AccessBucketURI {
+		return c.Domain
+	}
+	if c.Endpoint != "" {
+		return ctx.Bucket + "." + c.Endpoint
+	}
+	return ctx.Bucket + "." + strings.TrimPrefix(c.Domain, c.Bucket+".")
+}
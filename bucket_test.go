@@ -0,0 +1,241 @@
+package oss
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBucketAdmin(t *testing.T) {
+	buckets := map[string]bool{}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch {
+		case r.Method == http.MethodPut:
+			if r.Header.Get("x-amz-acl") != ACLPrivate || !strings.Contains(string(body), "<LocationConstraint>eu-west-1</LocationConstraint>") {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			buckets["tenant"] = true
+		case r.Method == http.MethodHead && !buckets["tenant"]:
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.RawQuery == "location=1":
+			w.Write([]byte(`<?xml version="1.0" encoding="UTF-8"?><LocationConstraint>eu-west-1</LocationConstraint>`))
+		case r.Method == http.MethodDelete:
+			delete(buckets, "tenant")
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Region, cfg.Bucket = "eu-west-1", "tenant"
+	admin, err := NewBucketAdmin(AWS, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := admin.HasBucket(ctx, "tenant"); ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if err = admin.CreateBucket(ctx, "tenant", ACLPrivate); err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := admin.HasBucket(ctx, "tenant"); !ok || err != nil {
+		t.Fatal(ok, err)
+	}
+	if location, err := admin.GetBucketLocation(ctx, "tenant"); location != "eu-west-1" || err != nil {
+		t.Fatal(location, err)
+	}
+	if err = admin.DeleteBucket(ctx, "tenant"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketDomain(t *testing.T) {
+	config := &StorageConfig{Access: "***", Secret: "***", Region: "us-east-1", Bucket: "main", Domain: "main.s3.amazonaws.com"}
+	set := mustStorage(NewStorageV4("", config, ProfileAWS)).(BucketStorage).HeadBucket("tenant")
+	if set.Url != "https://tenant.s3.amazonaws.com/" || set.Header["Host"] != "tenant.s3.amazonaws.com" {
+		t.Fatal(set.Url, set.Header)
+	}
+	set = mustStorage(NewStorageV2("", config, ProfileOBS)).(BucketStorage).GetBucketLocation("main")
+	if set.Url != "https://main.s3.amazonaws.com/?location=1" {
+		t.Fatal(set.Url)
+	}
+}
+
+func TestBucketLifecycle(t *testing.T) {
+	var content []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "lifecycle=1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			content, _ = io.ReadAll(r.Body)
+			if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(Md5(content)) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		case http.MethodGet:
+			if content == nil {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`<Error><Code>NoSuchLifecycleConfiguration</Code></Error>`))
+				return
+			}
+			w.Write(content)
+		case http.MethodDelete:
+			content = nil
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Signature, cfg.Region, cfg.Bucket = V2, "", "tenant"
+	admin, err := NewBucketAdmin(OSS, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = admin.GetBucketLifecycle(ctx, "tenant"); !IsNotFound(err) {
+		t.Fatal(err)
+	}
+	date := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	err = admin.PutBucketLifecycle(ctx, "tenant", &LifecycleConfiguration{Rules: []*LifecycleRule{
+		{
+			ID:                             "attachments",
+			Status:                         LifecycleEnabled,
+			Filter:                         &LifecycleFilter{And: &LifecycleAnd{Prefix: "mail/", Tags: []*Tag{{Key: "class", Value: "spam"}}}},
+			Expiration:                     &LifecycleExpiration{Date: &date},
+			Transitions:                    []*LifecycleTransition{{Days: 30, StorageClass: StorageClassIA}, {Days: 180, StorageClass: StorageClassArchive}},
+			NoncurrentVersionExpiration:    &NoncurrentVersionExpiration{NoncurrentDays: 7},
+			AbortIncompleteMultipartUpload: &AbortIncompleteMultipartUpload{DaysAfterInitiation: 1},
+		},
+		{Status: LifecycleDisabled, Expiration: &LifecycleExpiration{Days: 1}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"<Filter><And><Prefix>mail/</Prefix><Tag><Key>class</Key><Value>spam</Value></Tag></And></Filter>",
+		"<Date>2030-01-01T00:00:00Z</Date>",
+		"<Transition><Days>30</Days><StorageClass>IA</StorageClass></Transition>",
+		"<StorageClass>Archive</StorageClass>",
+		"<Status>Disabled</Status><Filter></Filter>",
+	} {
+		if !strings.Contains(string(content), s) {
+			t.Fatal(string(content))
+		}
+	}
+	config, err := admin.GetBucketLifecycle(ctx, "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(config.Rules) != 2 || !config.Rules[0].Expiration.Date.Equal(date) || config.Rules[0].Filter.And.Tags[0].Value != "spam" {
+		t.Fatalf("%+v", config.Rules[0])
+	}
+	if err = admin.DeleteBucketLifecycle(ctx, "tenant"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketCors(t *testing.T) {
+	var content []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.RawQuery != "cors=1" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		switch r.Method {
+		case http.MethodPut:
+			content, _ = io.ReadAll(r.Body)
+			if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(Md5(content)) {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		case http.MethodGet:
+			w.Write(content)
+		case http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Bucket = "tenant"
+	admin, err := NewBucketAdmin(AWS, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = admin.PutBucketCors(ctx, "tenant", &CORSConfiguration{Rules: []*CORSRule{{
+		AllowedOrigins: []string{"https://mail.example.com", "https://*.example.com"},
+		AllowedMethods: []string{http.MethodGet, http.MethodPut},
+		AllowedHeaders: []string{"*"},
+		ExposeHeaders:  []string{"ETag"},
+		MaxAgeSeconds:  3600,
+	}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), "<CORSRule><AllowedOrigin>https://mail.example.com</AllowedOrigin><AllowedOrigin>https://*.example.com</AllowedOrigin><AllowedMethod>GET</AllowedMethod>") {
+		t.Fatal(string(content))
+	}
+	config, err := admin.GetBucketCors(ctx, "tenant")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rule := config.Rules[0]; len(rule.AllowedMethods) != 2 || rule.ExposeHeaders[0] != "ETag" || rule.MaxAgeSeconds != 3600 {
+		t.Fatalf("%+v", rule)
+	}
+	if err = admin.DeleteBucketCors(ctx, "tenant"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBucketVersioning(t *testing.T) {
+	status := ""
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !r.URL.Query().Has("versioning") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		config := new(VersioningConfiguration)
+		switch r.Method {
+		case http.MethodPut:
+			xml.NewDecoder(r.Body).Decode(config)
+			status = config.Status
+		case http.MethodGet:
+			config.Status = status
+			xml.NewEncoder(w).Encode(config)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Signature, cfg.Region, cfg.Bucket = V5, "", "tenant"
+	admin, err := NewBucketAdmin(COS, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range []struct {
+		change func(ctx context.Context, bucket string) error
+		status string
+	}{
+		{nil, ""},
+		{admin.EnableBucketVersioning, VersioningEnabled},
+		{admin.SuspendBucketVersioning, VersioningSuspended},
+	} {
+		if c.change != nil {
+			if err = c.change(ctx, "tenant"); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if s, err := admin.GetBucketVersioning(ctx, "tenant"); s != c.status || err != nil {
+			t.Fatal(s, err)
+		}
+	}
+}
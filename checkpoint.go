@@ -0,0 +1,41 @@
+package oss
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// loadCheckpoint 读取JSON断点文件, 文件不存在返回os.ErrNotExist
+func loadCheckpoint(path string, v interface{}) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+// saveCheckpoint 先写临时文件再改名, 避免进程中断留下残缺的断点文件
+func saveCheckpoint(path string, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+	if _, err = tmp.Write(data); err == nil {
+		err = tmp.Sync()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -9,8 +9,10 @@ const (
 	AWS      = "aws"      // 亚马逊对象存储
 	MINIO    = "minio"    // minio对象存储
 	OSS      = "oss"      // 阿里云对象存储
+	COS      = "cos"      // 腾讯云对象存储
 	V2       = "v2"       // S3 V2签名算法
 	V4       = "v4"       // S3 V4签名算法
+	V5       = "v5"       // 腾讯云COS签名算法(q-sign-algorithm=sha1)
 	SHAKE256 = "SHAKE256" // 校验惟一性hash算法
 )
 
@@ -20,13 +22,16 @@ var profiles = map[string]*Profile{
 	AWS:   ProfileAWS,
 	MINIO: ProfileMINIO,
 	OSS:   ProfileOSS,
+	COS:   ProfileCOS,
 }
 
-type Signature func(prefix string, c *StorageConfig, p *Profile) Storage
+// Signature 按签名版本创建Storage, profile不支持该签名版本时返回错误
+type Signature func(prefix string, c *StorageConfig, p *Profile) (Storage, error)
 
 var signatures = map[string]Signature{
 	V2: NewStorageV2,
 	V4: NewStorageV4,
+	V5: NewStorageV5,
 }
 
 type ClientConfig struct {
@@ -59,21 +64,44 @@ type ClientConfig struct {
 	InsecureSkipVerify bool `json:"insecure_skip_verify"`
 }
 
+type RetryConfig struct {
+
+	// MaxAttempts 最大尝试次数(默认3, 设置1则不重试). 发起/完成分片上传只在连接未建立时重试
+	MaxAttempts int `json:"max_attempts"`
+
+	// BaseDelay 退避基础延时, 每次重试翻倍(默认100毫秒)
+	BaseDelay time.Duration `json:"base_delay"`
+
+	// MaxDelay 退避最大延时(默认5秒)
+	MaxDelay time.Duration `json:"max_delay"`
+
+	// RetryableStatus 可重试的http状态(默认429,500,502,503,504)
+	RetryableStatus []int `json:"retryable_status"`
+
+	// RetryableErrors 可重试的网络错误, 按errors.Is匹配(默认UnexpectedEOF,ECONNRESET,EPIPE等). 超时总是重试
+	RetryableErrors []error `json:"-"`
+}
+
 type StorageConfig struct {
-	Access      string `json:"access"`       // 访问ak
-	Secret      string `json:"secret"`       // 访问sk
-	Region      string `json:"region"`       // 区域
-	Bucket      string `json:"bucket"`       // 桶名
-	Domain      string `json:"domain"`       // 访问域名
-	ContentType string `json:"content_type"` // Content-Type, 默认二进制流application/octet-stream
+	Access      string              `json:"access"`       // 访问ak
+	Secret      string              `json:"secret"`       // 访问sk
+	Token       string              `json:"token"`        // 临时凭证(STS)的安全令牌, 为空表示长期凭证
+	Credentials CredentialsProvider `json:"-"`            // 凭证提供者, 设置后OSSI在每次请求前获取凭证(忽略Access/Secret/Token), 用于凭证轮换
+	Region      string              `json:"region"`       // 区域
+	Bucket      string              `json:"bucket"`       // 桶名
+	Domain      string              `json:"domain"`       // 访问域名
+	Endpoint    string              `json:"endpoint"`     // 服务域名(不含bucket), 用于桶操作. 为空时由Domain去掉"<Bucket>."得到
+	ContentType string              `json:"content_type"` // Content-Type, 默认二进制流application/octet-stream
 }
 
 // Config 对象存储服务统一配置
 type Config struct {
 	ClientConfig
 	StorageConfig
-	Signature string `json:"signature"` // 签名版本: V2,V4...默认V2
-	Prefix    string `json:"prefix"`    // key前缀
+	RetryConfig
+	Signature      string `json:"signature"`       // 签名版本: V2,V4,V5...默认V2
+	Prefix         string `json:"prefix"`          // key前缀
+	PayloadSigning bool   `json:"payload_signing"` // 上传时计算内容hash由服务端校验(V4为SHA256, V2/V5为MD5), 默认false
 }
 
 // 默认编码值
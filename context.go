@@ -3,13 +3,16 @@ package oss
 import (
 	"bytes"
 	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha1"
 	"crypto/sha256"
 	"encoding/xml"
 	"errors"
 	"net/http"
+	"net/url"
 	"sort"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
@@ -34,6 +37,11 @@ const (
 	headerAuthorization = "authorization"
 	headerContentType   = "content-type"
 	headerContentMD5    = "content-md5"
+	headerCacheControl  = "cache-control"
+	headerDisposition   = "content-disposition"
+	headerEncoding      = "content-encoding"
+	headerExpires       = "expires"
+	headerIfMatch       = "if-match"
 	headerRange         = "range"
 	headerHost          = "host"
 )
@@ -73,6 +81,12 @@ var contexts = sync.Pool{
 			SignedQueries: Values{
 				values: make([]*Value, 0, commonProviderQueriesInitSize),
 			},
+			Queries: Values{
+				values: make([]*Value, 0, commonProviderQueriesInitSize),
+			},
+			Headers: Values{
+				values: make([]*Value, 0, commonProviderHeadersInitSize),
+			},
 		}
 	},
 }
@@ -89,15 +103,20 @@ func returnContext(pc *ProviderContext) {
 
 // ProviderContext 请求选项,内部细节不对外暴露,通过Option进行设置!
 type ProviderContext struct {
-	UTC           time.Time // UTC时间
-	Status        int       // 预期返回的http-Status
-	Method        string    // 请求的http Method
-	ObjectKey     string    // 对象的key, 目前固定为上传文件的SHA1, 必须正确, 云存储开启sha1重命名后会导致文件"真空"!
-	ContentType   string    // 内容类型, 默认为空, 即服务器不检查内容类型
-	ContentMD5    string    // 内容MD5
-	SignedHeaders Values    // 需要加入签名的自定义头部
-	SignedQueries Values    // 需要加入签名的自与定义参数
-	Range         Range     // 需要Range查询
+	UTC           time.Time    // UTC时间
+	Status        int          // 预期返回的http-Status
+	Method        string       // 请求的http Method
+	ObjectKey     string       // 对象的key, 目前固定为上传文件的SHA1, 必须正确, 云存储开启sha1重命名后会导致文件"真空"!
+	ContentType   string       // 内容类型, 默认为空, 即服务器不检查内容类型
+	ContentMD5    string       // 内容MD5
+	ContentSha256 string       // 内容SHA256(hex), V4使用, 为空时按UNSIGNED-PAYLOAD签名
+	SignedHeaders Values       // 需要加入签名的自定义头部
+	SignedQueries Values       // 需要加入签名的自与定义参数
+	Queries       Values       // 不加入签名的参数(V2只签名子资源, 例如list-type,prefix等)
+	Headers       Values       // 不加入签名的头部(V2及阿里云V4只签名x-*头, 例如cache-control等)
+	Range         Range        // 需要Range查询
+	Credentials   *Credentials // 签名使用的凭证, 同一请求内保持一致
+	Bucket        string       // 桶操作指定的bucket, 为空使用StorageConfig.Bucket
 }
 
 func (a *ProviderContext) Reset() {
@@ -106,10 +125,15 @@ func (a *ProviderContext) Reset() {
 	a.ObjectKey = ""
 	a.ContentType = ""
 	a.ContentMD5 = ""
+	a.ContentSha256 = ""
 	a.SignedHeaders.Reset()
 	a.SignedQueries.Reset()
+	a.Queries.Reset()
+	a.Headers.Reset()
 	a.Range.Start = 0
 	a.Range.End = 0
+	a.Credentials = nil
+	a.Bucket = ""
 }
 
 type Value struct {
@@ -175,8 +199,8 @@ func (r Range) Value() string {
 
 	bf.WriteString("bytes=")
 	bf.WriteString(strconv.FormatUint(r.Start, 10))
+	bf.WriteByte('-') // End为0表示直到末尾: bytes=start-
 	if r.End > 0 {
-		bf.WriteByte('-')
 		bf.WriteString(strconv.FormatUint(r.End, 10))
 	}
 	return bf.String()
@@ -231,6 +255,77 @@ func ExtractMultipartUploadETag(rsp *http.Response) (string, error) {
 	return "", ErrEtagNotFound
 }
 
+// ListPartsResult 列举已上传分片结果
+type ListPartsResult struct {
+	XMLName              xml.Name    `xml:"ListPartsResult"`
+	Key                  string      `xml:"Key"`
+	UploadId             string      `xml:"UploadId"`
+	PartNumberMarker     int         `xml:"PartNumberMarker"`
+	NextPartNumberMarker int         `xml:"NextPartNumberMarker"`
+	MaxParts             int         `xml:"MaxParts"`
+	IsTruncated          bool        `xml:"IsTruncated"`
+	Parts                []*PartInfo `xml:"Part"`
+}
+
+// PartInfo 已上传分片, 内嵌Part可直接用于CompleteMultipartUpload
+type PartInfo struct {
+	Part
+	Size         int64     `xml:"Size"`
+	LastModified time.Time `xml:"LastModified"`
+}
+
+// CompletedParts 转为Parts, 可直接用于CompleteMultipartUpload
+func (r *ListPartsResult) CompletedParts() Parts {
+	parts := make(Parts, len(r.Parts))
+	for i, p := range r.Parts {
+		parts[i] = &Part{PartNumber: p.PartNumber, ETag: p.ETag}
+	}
+	return parts
+}
+
+func ExtractListPartsResult(rsp *http.Response) (*ListPartsResult, error) {
+
+	result := new(ListPartsResult)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// ListMultipartUploadsResult 列举未完成的分片上传结果
+type ListMultipartUploadsResult struct {
+	XMLName            xml.Name  `xml:"ListMultipartUploadsResult"`
+	Prefix             string    `xml:"Prefix"`
+	KeyMarker          string    `xml:"KeyMarker"`
+	UploadIdMarker     string    `xml:"UploadIdMarker"`
+	NextKeyMarker      string    `xml:"NextKeyMarker"`
+	NextUploadIdMarker string    `xml:"NextUploadIdMarker"`
+	MaxUploads         int       `xml:"MaxUploads"`
+	IsTruncated        bool      `xml:"IsTruncated"`
+	Uploads            []*Upload `xml:"Upload"`
+}
+
+// Upload 未完成的分片上传
+type Upload struct {
+	Key          string    `xml:"Key"`
+	UploadId     string    `xml:"UploadId"`
+	Initiated    time.Time `xml:"Initiated"`
+	StorageClass string    `xml:"StorageClass"`
+}
+
+func ExtractListMultipartUploadsResult(rsp *http.Response) (*ListMultipartUploadsResult, error) {
+
+	result := new(ListMultipartUploadsResult)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 func CompleteMultipartUploadParts(buffer *bytes.Buffer, parts []*Part) error {
 	content := &completeMultipartUpload{
 		Parts: parts,
@@ -238,6 +333,181 @@ func CompleteMultipartUploadParts(buffer *bytes.Buffer, parts []*Part) error {
 	return xml.NewEncoder(buffer).Encode(content)
 }
 
+/****************************************
+ * head object 辅助数据结构
+ ****************************************/
+
+// ObjectInfo 对象元数据
+type ObjectInfo struct {
+	ContentLength        int64             // 对象大小
+	ContentType          string            // 内容类型
+	ETag                 string            // ETag(带引号)
+	LastModified         time.Time         // 最后修改时间
+	StorageClass         string            // 存储类型(标准存储可能为空)
+	ServerSideEncryption string            // 服务端加密算法
+	VersionId            string            // 版本ID
+	Metadata             map[string]string // 用户元数据, key已去除profile的前缀并小写
+}
+
+func ExtractObjectInfo(rsp *http.Response, p *Profile) (*ObjectInfo, error) {
+	info := &ObjectInfo{
+		ContentLength:        rsp.ContentLength,
+		ContentType:          rsp.Header.Get("Content-Type"),
+		ETag:                 rsp.Header.Get("Etag"),
+		StorageClass:         rsp.Header.Get(p.StorageClassHeader),
+		ServerSideEncryption: rsp.Header.Get(p.EncryptionHeader),
+		VersionId:            rsp.Header.Get(p.VersionIdHeader),
+		Metadata:             make(map[string]string),
+	}
+	for k, vs := range rsp.Header {
+		// Header的名称已经标准化(例如X-Amz-Meta-Foo), 统一小写后比较前缀
+		if name := strings.ToLower(k); strings.HasPrefix(name, p.MetaHeaderPrefix) && len(vs) > 0 {
+			info.Metadata[name[len(p.MetaHeaderPrefix):]] = vs[0]
+		}
+	}
+	if v := rsp.Header.Get("Last-Modified"); v != "" {
+		t, err := http.ParseTime(v)
+		if err != nil {
+			return nil, err
+		}
+		info.LastModified = t
+	}
+	return info, nil
+}
+
+/****************************************
+ * copy object 辅助数据结构
+ ****************************************/
+
+// CopyResult 复制对象或复制分片结果
+type CopyResult struct {
+	XMLName         xml.Name  // CopyObjectResult或CopyPartResult
+	ETag            string    `xml:"ETag"`
+	LastModified    time.Time `xml:"LastModified"`
+	VersionId       string    `xml:"-"` // 开启多版本时新对象的版本ID
+	SourceVersionId string    `xml:"-"` // 开启多版本时复制源的版本ID
+}
+
+// ExtractCopyResult 复制可能返回200但内容是Error, 需要按根元素区分
+func ExtractCopyResult(rsp *http.Response, p *Profile) (*CopyResult, error) {
+	buf := borrowBuffer()
+	defer returnBuffer(buf)
+
+	if _, err := buf.ReadFrom(rsp.Body); err != nil {
+		return nil, err
+	}
+	result := new(CopyResult)
+	if err := xml.Unmarshal(buf.Bytes(), result); err != nil {
+		return nil, err
+	}
+	if result.XMLName.Local == "Error" {
+		e := &Error{StatusCode: rsp.StatusCode}
+		if err := xml.Unmarshal(buf.Bytes(), e); err != nil {
+			return nil, err
+		}
+		if p.RequestIdHeader != "" {
+			e.HeaderRequestId = rsp.Header.Get(p.RequestIdHeader)
+		}
+		return nil, e
+	}
+	result.VersionId = rsp.Header.Get(p.VersionIdHeader)
+	result.SourceVersionId = rsp.Header.Get(p.CopySourceHeader + "-version-id")
+	return result, nil
+}
+
+/****************************************
+ * delete objects 辅助数据结构
+ ****************************************/
+
+// deleteObjects 批量删除请求
+type deleteObjects struct {
+	XMLName xml.Name        `xml:"Delete"`
+	Quiet   bool            `xml:"Quiet"`
+	Objects []*deleteObject `xml:"Object"`
+}
+
+type deleteObject struct {
+	Key string `xml:"Key"`
+}
+
+// DeleteResult 批量删除结果, Quiet模式只返回失败的key
+type DeleteResult struct {
+	XMLName xml.Name         `xml:"DeleteResult"`
+	Deleted []*DeletedObject `xml:"Deleted"`
+	Errors  []*DeleteError   `xml:"Error"`
+}
+
+type DeletedObject struct {
+	Key string `xml:"Key"`
+}
+
+type DeleteError struct {
+	Key     string `xml:"Key"`
+	Code    string `xml:"Code"`
+	Message string `xml:"Message"`
+}
+
+func DeleteObjectsKeys(buffer *bytes.Buffer, keys []string, quiet bool) error {
+	content := &deleteObjects{
+		Quiet:   quiet,
+		Objects: make([]*deleteObject, len(keys)),
+	}
+	for i, k := range keys {
+		content.Objects[i] = &deleteObject{Key: k}
+	}
+	return xml.NewEncoder(buffer).Encode(content)
+}
+
+func ExtractDeleteResult(rsp *http.Response) (*DeleteResult, error) {
+
+	result := new(DeleteResult)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+/****************************************
+ * list objects 辅助数据结构
+ ****************************************/
+
+// ListObjectsResult 列举对象结果(ListObjectsV2)
+type ListObjectsResult struct {
+	XMLName               xml.Name       `xml:"ListBucketResult"`
+	Prefix                string         `xml:"Prefix"`
+	Delimiter             string         `xml:"Delimiter"`
+	MaxKeys               int            `xml:"MaxKeys"`
+	KeyCount              int            `xml:"KeyCount"`
+	IsTruncated           bool           `xml:"IsTruncated"`
+	ContinuationToken     string         `xml:"ContinuationToken"`
+	NextContinuationToken string         `xml:"NextContinuationToken"`
+	NextMarker            string         `xml:"NextMarker"` // 仅marker分页(如COS), 未返回时为最后一个key
+	Objects               []*ObjectEntry `xml:"Contents"`
+	CommonPrefixes        []string       `xml:"CommonPrefixes>Prefix"`
+}
+
+// ObjectEntry 列举结果中的对象
+type ObjectEntry struct {
+	Key          string    `xml:"Key"`
+	Size         int64     `xml:"Size"`
+	ETag         string    `xml:"ETag"`
+	LastModified time.Time `xml:"LastModified"`
+	StorageClass string    `xml:"StorageClass"`
+}
+
+func ExtractListObjectsResult(rsp *http.Response) (*ListObjectsResult, error) {
+
+	result := new(ListObjectsResult)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
 /****************************************
  * 辅助工具方法
  ****************************************/
@@ -250,12 +520,24 @@ func If(c bool, t, f string) string {
 	}
 }
 
+func Md5(p []byte) []byte {
+	h := md5.New()
+	h.Write(p)
+	return h.Sum(nil)
+}
+
 func Sha256(p []byte) []byte {
 	h := sha256.New()
 	h.Write(p)
 	return h.Sum(nil)
 }
 
+func Sha1(p []byte) []byte {
+	h := sha1.New()
+	h.Write(p)
+	return h.Sum(nil)
+}
+
 func HmacSha256(key []byte, val []byte) []byte {
 	h := hmac.New(sha256.New, key)
 	h.Write(val)
@@ -268,6 +550,38 @@ func HmacSha1(key []byte, val []byte) []byte {
 	return h.Sum(nil)
 }
 
+// UriEncode 按RFC3986编码(仅保留unreserved字符), encodeSlash决定是否编码'/'
+func UriEncode(s string, encodeSlash bool) string {
+	const hex = "0123456789ABCDEF"
+
+	bf := borrowBuffer()
+	defer returnBuffer(bf)
+
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			bf.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			bf.WriteByte(b)
+		default:
+			bf.WriteByte('%')
+			bf.WriteByte(hex[b>>4])
+			bf.WriteByte(hex[b&15])
+		}
+	}
+	return bf.String()
+}
+
+// CopySource 复制源header的值: /bucket/key(key需要URL编码), 指定版本时为/bucket/key?versionId=xxx
+func CopySource(bucket string, key string, versionId string) string {
+	if versionId != "" {
+		return "/" + bucket + "/" + UriEncode(key, false) + "?versionId=" + url.QueryEscape(versionId)
+	}
+	return "/" + bucket + "/" + UriEncode(key, false)
+}
+
 // UnsafeBytes converts string to byte slice without a memory allocation.
 // For more details, see https://github.com/golang/go/issues/53003#issuecomment-1140276077.
 func UnsafeBytes(s string) []byte {
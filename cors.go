@@ -0,0 +1,71 @@
+package oss
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+)
+
+// CORSConfiguration 桶的跨域配置(S3的?cors格式)
+type CORSConfiguration struct {
+	XMLName xml.Name    `xml:"CORSConfiguration"`
+	Rules   []*CORSRule `xml:"CORSRule"`
+}
+
+// CORSRule 跨域规则, 浏览器请求匹配第一条满足Origin及Method的规则
+type CORSRule struct {
+	ID             string   `xml:"ID,omitempty"`
+	AllowedOrigins []string `xml:"AllowedOrigin"`           // 允许的来源, 支持一个"*"通配符, 例如https://*.example.com
+	AllowedMethods []string `xml:"AllowedMethod"`           // 允许的方法: GET, PUT, POST, DELETE, HEAD
+	AllowedHeaders []string `xml:"AllowedHeader,omitempty"` // 预检请求Access-Control-Request-Headers允许的header
+	ExposeHeaders  []string `xml:"ExposeHeader,omitempty"`  // 允许浏览器读取的响应header, 例如ETag
+	MaxAgeSeconds  int      `xml:"MaxAgeSeconds,omitempty"` // 预检结果的缓存时间(秒)
+}
+
+/*
+PutBucketCors 设置桶的跨域规则(覆盖原有规则)
+*/
+func (b *bucketAdminImpl) PutBucketCors(ctx context.Context, bucket string, config *CORSConfiguration) error {
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	return b.putBucketContent(ctx, body, func(s BucketStorage, contentMD5 string) *RequestSetting {
+		return s.PutBucketCors(bucket, contentMD5)
+	})
+}
+
+// GetBucketCors 未设置规则时返回ErrNotFound(NoSuchCORSConfiguration)
+func (b *bucketAdminImpl) GetBucketCors(ctx context.Context, bucket string) (*CORSConfiguration, error) {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).GetBucketCors(bucket) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer discardResponseBody(rsp)
+
+	return ExtractCORSConfiguration(rsp)
+}
+
+func (b *bucketAdminImpl) DeleteBucketCors(ctx context.Context, bucket string) error {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).DeleteBucketCors(bucket) },
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+func ExtractCORSConfiguration(rsp *http.Response) (*CORSConfiguration, error) {
+
+	result := new(CORSConfiguration)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
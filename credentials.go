@@ -0,0 +1,276 @@
+package oss
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNoCredentials 未找到可用的访问凭证
+var ErrNoCredentials = errors.New("no credentials")
+
+const (
+	defaultCredentialsExpiryWindow = time.Minute      // 凭证提前过期的时间窗口, 避免签名后请求到达时已过期
+	defaultCredentialsFileInterval = 10 * time.Second // JSON凭证文件检查变更的间隔
+	defaultCredentialsRefresh      = 5 * time.Minute  // 不过期凭证(环境变量, 共享凭证文件等)的刷新间隔
+)
+
+// Credentials 访问凭证
+type Credentials struct {
+	Access  string    `json:"access"`  // 访问ak
+	Secret  string    `json:"secret"`  // 访问sk
+	Token   string    `json:"token"`   // 临时凭证(STS)的安全令牌
+	Expires time.Time `json:"expires"` // 过期时间, 零值表示不过期
+}
+
+// expired 判断凭证是否(即将)过期
+func (c *Credentials) expired(now time.Time) bool {
+	return !c.Expires.IsZero() && !now.Before(c.Expires.Add(-defaultCredentialsExpiryWindow))
+}
+
+// CredentialsProvider 访问凭证提供者, 在每次请求(含重试)签名前调用. 实现需要并发安全
+type CredentialsProvider interface {
+	Retrieve() (*Credentials, error)
+}
+
+/*================================*\
+	凭证缓存
+\*================================*/
+
+// credentialsCache 缓存凭证直到过期, 过期后再向provider获取. 不过期的凭证按interval定期刷新
+type credentialsCache struct {
+	provider CredentialsProvider
+	interval time.Duration
+	mutex    sync.Mutex
+	current  *Credentials
+	refresh  time.Time // 不过期凭证的刷新时间
+}
+
+// NewCredentialsCache 缓存provider的凭证直到过期(不过期的凭证每5分钟刷新). New会自动为Config.Credentials添加缓存
+func NewCredentialsCache(provider CredentialsProvider) CredentialsProvider {
+	if c, ok := provider.(*credentialsCache); ok {
+		return c
+	}
+	return &credentialsCache{provider: provider, interval: defaultCredentialsRefresh}
+}
+
+func (c *credentialsCache) Retrieve() (*Credentials, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	now := time.Now()
+	if c.current != nil && !c.current.expired(now) && (!c.current.Expires.IsZero() || now.Before(c.refresh)) {
+		return c.current, nil
+	}
+	cred, err := c.provider.Retrieve()
+	if err != nil {
+		return nil, err
+	}
+	c.current = cred
+	c.refresh = now.Add(c.interval)
+	return cred, nil
+}
+
+/*================================*\
+	静态凭证
+\*================================*/
+
+// StaticProvider 固定的访问凭证, 即StorageConfig中的Access/Secret/Token
+type StaticProvider struct {
+	Credentials
+}
+
+func NewStaticProvider(access string, secret string, token string) *StaticProvider {
+	return &StaticProvider{Credentials{Access: access, Secret: secret, Token: token}}
+}
+
+func (p *StaticProvider) Retrieve() (*Credentials, error) {
+	if p.Access == "" || p.Secret == "" {
+		return nil, ErrNoCredentials
+	}
+	cred := p.Credentials
+	return &cred, nil
+}
+
+/*================================*\
+	环境变量凭证
+\*================================*/
+
+// EnvProvider 从环境变量读取凭证, 变量名未指定时使用AWS约定的名称
+type EnvProvider struct {
+	AccessEnv string `json:"access_env"` // 默认AWS_ACCESS_KEY_ID
+	SecretEnv string `json:"secret_env"` // 默认AWS_SECRET_ACCESS_KEY
+	TokenEnv  string `json:"token_env"`  // 默认AWS_SESSION_TOKEN
+}
+
+func (p *EnvProvider) Retrieve() (*Credentials, error) {
+	cred := &Credentials{
+		Access: os.Getenv(nvlS(p.AccessEnv, "AWS_ACCESS_KEY_ID")),
+		Secret: os.Getenv(nvlS(p.SecretEnv, "AWS_SECRET_ACCESS_KEY")),
+		Token:  os.Getenv(nvlS(p.TokenEnv, "AWS_SESSION_TOKEN")),
+	}
+	if cred.Access == "" || cred.Secret == "" {
+		return nil, fmt.Errorf("env: %w", ErrNoCredentials)
+	}
+	return cred, nil
+}
+
+/*================================*\
+	共享凭证文件(AWS INI格式)
+\*================================*/
+
+/*
+SharedFileProvider 读取AWS风格的共享凭证文件, 例如:
+
+	[default]
+	aws_access_key_id = AKID
+	aws_secret_access_key = SECRET
+	aws_session_token = TOKEN
+*/
+type SharedFileProvider struct {
+	Filename string `json:"filename"` // 默认$AWS_SHARED_CREDENTIALS_FILE或~/.aws/credentials
+	Profile  string `json:"profile"`  // 默认$AWS_PROFILE或default
+}
+
+func (p *SharedFileProvider) Retrieve() (*Credentials, error) {
+	filename := nvlS(p.Filename, os.Getenv("AWS_SHARED_CREDENTIALS_FILE"))
+	if filename == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		filename = filepath.Join(home, ".aws", "credentials")
+	}
+	profile := nvlS(p.Profile, nvlS(os.Getenv("AWS_PROFILE"), "default"))
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	cred := new(Credentials)
+	section := ""
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+		if line[0] == '[' && line[len(line)-1] == ']' {
+			section = strings.TrimSpace(line[1 : len(line)-1])
+			continue
+		}
+		if section != profile {
+			continue
+		}
+		name, value, ok := strings.Cut(line, "=")
+		if !ok {
+			continue
+		}
+		switch strings.TrimSpace(name) {
+		case "aws_access_key_id":
+			cred.Access = strings.TrimSpace(value)
+		case "aws_secret_access_key":
+			cred.Secret = strings.TrimSpace(value)
+		case "aws_session_token":
+			cred.Token = strings.TrimSpace(value)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cred.Access == "" || cred.Secret == "" {
+		return nil, fmt.Errorf("%s [%s]: %w", filename, profile, ErrNoCredentials)
+	}
+	return cred, nil
+}
+
+/*================================*\
+	JSON凭证文件(监视变更)
+\*================================*/
+
+/*
+FileProvider 读取JSON凭证文件(格式同Credentials), 文件修改后自动重新加载.
+返回凭证的过期时间不超过检查间隔, 使缓存定期回到provider检查文件变更.
+*/
+type FileProvider struct {
+	Filename string        `json:"filename"` // JSON凭证文件
+	Interval time.Duration `json:"interval"` // 检查变更的间隔(默认10秒)
+
+	mutex   sync.Mutex
+	modTime time.Time
+	current *Credentials
+}
+
+func (p *FileProvider) Retrieve() (*Credentials, error) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	fi, err := os.Stat(p.Filename)
+	if err != nil {
+		return nil, err
+	}
+	if p.current == nil || !fi.ModTime().Equal(p.modTime) {
+		cred := new(Credentials)
+		if err = loadCheckpoint(p.Filename, cred); err != nil {
+			return nil, err
+		}
+		if cred.Access == "" || cred.Secret == "" {
+			return nil, fmt.Errorf("%s: %w", p.Filename, ErrNoCredentials)
+		}
+		p.current = cred
+		p.modTime = fi.ModTime()
+	}
+
+	cred := *p.current
+	// 加上提前过期窗口, 保证缓存按检查间隔刷新
+	check := time.Now().Add(NvlD(p.Interval, defaultCredentialsFileInterval) + defaultCredentialsExpiryWindow)
+	if cred.Expires.IsZero() || check.Before(cred.Expires) {
+		cred.Expires = check
+	}
+	return &cred, nil
+}
+
+/*================================*\
+	凭证链
+\*================================*/
+
+// ChainProvider 按顺序尝试各provider, 返回第一个成功获取的凭证
+type ChainProvider struct {
+	Providers []CredentialsProvider
+}
+
+func NewChainProvider(providers ...CredentialsProvider) *ChainProvider {
+	return &ChainProvider{Providers: providers}
+}
+
+func (p *ChainProvider) Retrieve() (*Credentials, error) {
+	var errs []error
+	for _, v := range p.Providers {
+		cred, err := v.Retrieve()
+		if err == nil {
+			return cred, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, errors.Join(append(errs, ErrNoCredentials)...)
+}
+
+// configCredentials 签名时使用的凭证, 取StorageConfig的Access/Secret/Token.
+// 设置provider时由OSSI在每次请求前获取凭证并绑定到storage(见withCredentials)
+func configCredentials(c *StorageConfig) *Credentials {
+	return &Credentials{Access: c.Access, Secret: c.Secret, Token: c.Token}
+}
+
+func nvlS(val, def string) string {
+	if val == "" {
+		return def
+	}
+	return val
+}
@@ -0,0 +1,161 @@
+package oss
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestChainProvider(t *testing.T) {
+	dir := t.TempDir()
+	shared := filepath.Join(dir, "credentials")
+	os.WriteFile(shared, []byte("[default]\naws_access_key_id = default\naws_secret_access_key = x\n\n[rotated]\naws_access_key_id = AKID\naws_secret_access_key = SECRET\naws_session_token = TOKEN\n"), 0600)
+
+	chain := NewChainProvider(
+		&EnvProvider{AccessEnv: "OSS_TEST_NOT_SET", SecretEnv: "OSS_TEST_NOT_SET"},
+		&SharedFileProvider{Filename: shared, Profile: "rotated"},
+		NewStaticProvider("static", "static", ""),
+	)
+	cred, err := chain.Retrieve()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cred.Access != "AKID" || cred.Secret != "SECRET" || cred.Token != "TOKEN" {
+		t.Fatalf("%+v", cred)
+	}
+
+	_, err = NewChainProvider(&EnvProvider{AccessEnv: "OSS_TEST_NOT_SET"}).Retrieve()
+	if !errors.Is(err, ErrNoCredentials) {
+		t.Fatal(err)
+	}
+}
+
+func TestFileProviderRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	os.WriteFile(path, []byte(`{"access":"old","secret":"old"}`), 0600)
+
+	var mutex sync.Mutex
+	var accesses []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, credential, _ := strings.Cut(r.Header.Get("authorization"), "Credential=")
+		access, _, _ := strings.Cut(credential, "/")
+		mutex.Lock()
+		accesses = append(accesses, access)
+		mutex.Unlock()
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Credentials = &FileProvider{Filename: path, Interval: time.Millisecond}
+	o := mustNew(AWS, cfg)
+	if _, err := o.HeadObject(ctx, "rotation"); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(path, []byte(`{"access":"new","secret":"new"}`), 0600)
+	os.Chtimes(path, time.Now(), time.Now().Add(time.Second)) // 保证修改时间变化
+	time.Sleep(10 * time.Millisecond)
+	if _, err := o.HeadObject(ctx, "rotation"); err != nil {
+		t.Fatal(err)
+	}
+	mutex.Lock()
+	defer mutex.Unlock()
+	if strings.Join(accesses, ",") != "old,new" {
+		t.Fatal(accesses)
+	}
+}
+
+func TestCredentialsError(t *testing.T) {
+	var requests int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Credentials = &EnvProvider{AccessEnv: "OSS_TEST_NOT_SET"}
+	o := mustNew(AWS, cfg)
+	if _, err := o.HeadObject(ctx, "missing"); !errors.Is(err, ErrNoCredentials) {
+		t.Fatal(err)
+	}
+	if requests != 0 {
+		t.Fatalf("requests: %d", requests)
+	}
+}
+
+// expiringProvider 首次返回已过期的凭证(缓存不生效), 之后获取失败
+type expiringProvider struct {
+	calls int32
+}
+
+func (p *expiringProvider) Retrieve() (*Credentials, error) {
+	if atomic.AddInt32(&p.calls, 1) > 1 {
+		return nil, ErrNoCredentials
+	}
+	return &Credentials{Access: "once", Secret: "once", Expires: time.Now()}, nil
+}
+
+func TestCredentialsRetrieveOnce(t *testing.T) {
+	var accesses []string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, credential, _ := strings.Cut(r.Header.Get("authorization"), "Credential=")
+		access, _, _ := strings.Cut(credential, "/")
+		accesses = append(accesses, access)
+	}))
+	defer srv.Close()
+
+	provider := new(expiringProvider)
+	cfg := newTestConfig(srv)
+	cfg.Credentials = provider
+	o := mustNew(AWS, cfg)
+	if cfg.Credentials != provider {
+		t.Fatal("config credentials replaced")
+	}
+	// 每次请求只获取一次凭证, 签名使用获取到的凭证
+	if _, err := o.HeadObject(ctx, "once"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(accesses, ",") != "once" {
+		t.Fatal(accesses)
+	}
+	// 凭证过期后获取失败, 返回错误而不是使用空凭证签名
+	if _, err := o.HeadObject(ctx, "once"); !errors.Is(err, ErrNoCredentials) {
+		t.Fatal(err)
+	}
+	if _, err := o.PresignURL(ctx, http.MethodGet, "once", 60); !errors.Is(err, ErrNoCredentials) {
+		t.Fatal(err)
+	}
+	if _, err := o.GetObjectLink(ctx, "once", 60); !errors.Is(err, ErrNoCredentials) {
+		t.Fatal(err)
+	}
+	if len(accesses) != 1 {
+		t.Fatal(accesses)
+	}
+}
+
+func TestCredentialsCacheRefresh(t *testing.T) {
+	t.Setenv("OSS_TEST_ACCESS", "old")
+	t.Setenv("OSS_TEST_SECRET", "secret")
+	cache := &credentialsCache{
+		provider: &EnvProvider{AccessEnv: "OSS_TEST_ACCESS", SecretEnv: "OSS_TEST_SECRET"},
+		interval: 50 * time.Millisecond,
+	}
+	if cred, err := cache.Retrieve(); err != nil || cred.Access != "old" {
+		t.Fatal(cred, err)
+	}
+	// 不过期的凭证在刷新间隔内使用缓存, 之后重新读取
+	os.Setenv("OSS_TEST_ACCESS", "new")
+	if cred, _ := cache.Retrieve(); cred.Access != "old" {
+		t.Fatal(cred.Access)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if cred, _ := cache.Retrieve(); cred.Access != "new" {
+		t.Fatal(cred.Access)
+	}
+}
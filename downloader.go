@@ -0,0 +1,239 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	defaultDownloaderPartSize    = 8 * 1024 * 1024 // 默认分段大小
+	defaultDownloaderConcurrency = 4               // 默认并发下载数
+)
+
+type DownloaderConfig struct {
+	PartSize    int64 `json:"part_size"`   // 分段大小(默认8M)
+	Concurrency int   `json:"concurrency"` // 并发下载数(默认4)
+}
+
+// Downloader 基于GetObject的Range并发下载
+type Downloader struct {
+	ossi        OSSI
+	partSize    int64
+	concurrency int
+}
+
+func NewDownloader(o OSSI, c *DownloaderConfig) *Downloader {
+	if c == nil {
+		c = new(DownloaderConfig)
+	}
+	d := &Downloader{
+		ossi:        o,
+		partSize:    c.PartSize,
+		concurrency: NvlI(c.Concurrency, defaultDownloaderConcurrency),
+	}
+	if d.partSize <= 0 {
+		d.partSize = defaultDownloaderPartSize
+	}
+	return d
+}
+
+/*
+Download 并发下载对象到w, 返回下载的总字节数.
+各分段以HEAD得到的ETag(If-Match)及版本ID读取, 下载期间对象被修改时IsPreconditionFailed(err)为true
+*/
+func (d *Downloader) Download(c context.Context, ossKey string, w io.WriterAt) (int64, error) {
+	info, err := d.ossi.HeadObject(c, ossKey)
+	if err != nil {
+		return 0, err
+	}
+	cp := &downloadCheckpoint{
+		Key:       ossKey,
+		ETag:      info.ETag,
+		VersionId: info.VersionId,
+		Size:      info.ContentLength,
+		PartSize:  d.partSize,
+	}
+	return d.downloadParts(c, w, cp, nil)
+}
+
+// downloadCheckpoint 断点下载记录, 保存于本地JSON文件
+type downloadCheckpoint struct {
+	Key          string    `json:"key"`
+	ETag         string    `json:"etag"`       // 分段请求的If-Match
+	VersionId    string    `json:"version_id"` // 开启多版本时分段请求的versionId
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	FilePath     string    `json:"file_path"`
+	PartSize     int64     `json:"part_size"`
+	Done         []int     `json:"done"` // 已完成的分段序号(从0开始)
+}
+
+/*
+DownloadFile 断点下载对象到本地文件. 已完成的分段记录在checkpointPath, 中断后再次调用只下载缺失的分段.
+对象ETag, 版本或大小变化时重新下载. 成功后删除断点文件.
+*/
+func (d *Downloader) DownloadFile(c context.Context, ossKey string, filePath string, checkpointPath string) (int64, error) {
+	info, err := d.ossi.HeadObject(c, ossKey)
+	if err != nil {
+		return 0, err
+	}
+
+	cp := new(downloadCheckpoint)
+	if err = loadCheckpoint(checkpointPath, cp); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, err
+	}
+	if cp.Key != ossKey || cp.FilePath != filePath || cp.ETag != info.ETag || cp.VersionId != info.VersionId || cp.Size != info.ContentLength ||
+		!cp.LastModified.Equal(info.LastModified) || cp.PartSize <= 0 {
+		cp = &downloadCheckpoint{
+			Key:          ossKey,
+			ETag:         info.ETag,
+			VersionId:    info.VersionId,
+			Size:         info.ContentLength,
+			LastModified: info.LastModified,
+			FilePath:     filePath,
+			PartSize:     d.partSize,
+		}
+	}
+
+	f, err := os.OpenFile(filePath, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+	if err = f.Truncate(cp.Size); err != nil {
+		return 0, err
+	}
+
+	n, err := d.downloadParts(c, f, cp, func() error {
+		// 先落盘再记录断点
+		if err := f.Sync(); err != nil {
+			return err
+		}
+		return saveCheckpoint(checkpointPath, cp)
+	})
+	if err != nil {
+		return n, err
+	}
+	os.Remove(checkpointPath)
+	return n, nil
+}
+
+// downloadParts 并发下载cp中未完成的分段, 每完成一个分段调用saved(可为nil)
+func (d *Downloader) downloadParts(c context.Context, w io.WriterAt, cp *downloadCheckpoint, saved func() error) (int64, error) {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	done := make(map[int]bool, len(cp.Done))
+	for _, i := range cp.Done {
+		done[i] = true
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		total    int64
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	count := int((cp.Size + cp.PartSize - 1) / cp.PartSize)
+	for i := range done {
+		if i < count {
+			total += d.partLength(cp, i)
+		}
+	}
+
+	indexes := make(chan int)
+	for i := 0; i < d.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				n, err := d.downloadPart(ctx, w, cp, i)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				mu.Lock()
+				total += n
+				cp.Done = append(cp.Done, i)
+				if saved != nil {
+					err = saved()
+				}
+				mu.Unlock()
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+loop:
+	for i := 0; i < count; i++ {
+		if done[i] {
+			continue
+		}
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return total, firstErr
+	}
+	if err := c.Err(); err != nil {
+		return total, err
+	}
+	if total != cp.Size {
+		return total, fmt.Errorf("download size mismatch: %d != %d", total, cp.Size)
+	}
+	return total, nil
+}
+
+func (d *Downloader) partLength(cp *downloadCheckpoint, i int) int64 {
+	start := int64(i) * cp.PartSize
+	if cp.Size-start < cp.PartSize {
+		return cp.Size - start
+	}
+	return cp.PartSize
+}
+
+// downloadPart 下载第i个分段. 失败重试由ossi完成, 这里不再重试
+func (d *Downloader) downloadPart(ctx context.Context, w io.WriterAt, cp *downloadCheckpoint, i int) (int64, error) {
+	start := int64(i) * cp.PartSize
+	length := d.partLength(cp, i)
+
+	// 固定为HEAD时的对象, 避免下载期间对象被覆盖导致各分段来自不同版本
+	_, rc, err := d.ossi.GetObject(ctx, cp.Key, &Range{Start: uint64(start), End: uint64(start + length - 1)},
+		&ObjectOptions{VersionId: cp.VersionId, IfMatch: cp.ETag})
+	if err != nil {
+		return 0, err
+	}
+	defer rc.Close()
+
+	// 多读1字节用于校验长度
+	n, err := io.Copy(io.NewOffsetWriter(w, start), io.LimitReader(rc, length+1))
+	if err != nil {
+		return n, err
+	}
+	if n != length {
+		return n, fmt.Errorf("range %d-%d: %w", start, start+length-1, io.ErrUnexpectedEOF)
+	}
+	return n, nil
+}
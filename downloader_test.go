@@ -0,0 +1,98 @@
+package oss
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDownloaderDownloadFile(t *testing.T) {
+	data := make([]byte, 3*1024*1024+7)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	modtime := time.Now()
+	var failures int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Range") == "bytes=1048576-2097151" && failures < 1 {
+			failures++
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("ETag", `"1"`)
+		http.ServeContent(w, r, "", modtime, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "archive")
+	checkpointPath := filepath.Join(dir, "archive.cp")
+
+	d := NewDownloader(newTestOSSI(srv), &DownloaderConfig{PartSize: 1024 * 1024, Concurrency: 1})
+	if _, err := d.DownloadFile(ctx, ossKey, filePath, checkpointPath); !IsAccessDenied(err) {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(checkpointPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// 续传跳过已完成的分段
+	n, err := d.DownloadFile(ctx, ossKey, filePath, checkpointPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, _ := os.ReadFile(filePath)
+	if n != int64(len(data)) || !bytes.Equal(got, data) {
+		t.Fatalf("downloaded %d bytes", n)
+	}
+	if _, err := os.Stat(checkpointPath); !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestDownloaderPreconditionFailed(t *testing.T) {
+	data := make([]byte, 2*1024*1024)
+	modtime := time.Now()
+	var mu sync.Mutex
+	var gets int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		etag := `"1"`
+		if r.Method == http.MethodGet {
+			if r.URL.Query().Get("versionId") != "v1" {
+				t.Errorf("versionId: %q", r.URL.RawQuery)
+			}
+			if r.Header.Get("If-Match") != `"1"` {
+				t.Errorf("If-Match: %q", r.Header.Get("If-Match"))
+			}
+			// 第一个分段之后对象被覆盖
+			if gets++; gets > 1 {
+				etag = `"2"`
+			}
+		}
+		w.Header().Set("ETag", etag)
+		w.Header().Set("x-amz-version-id", "v1")
+		http.ServeContent(w, r, "", modtime, bytes.NewReader(data))
+	}))
+	defer srv.Close()
+
+	d := NewDownloader(newTestOSSI(srv), &DownloaderConfig{PartSize: 1024 * 1024, Concurrency: 1})
+	f, err := os.Create(filepath.Join(t.TempDir(), "archive"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err = d.Download(ctx, ossKey, f); !IsPreconditionFailed(err) {
+		t.Fatal(err)
+	}
+	// 412不重试
+	if gets != 2 {
+		t.Fatalf("gets: %d", gets)
+	}
+}
@@ -0,0 +1,110 @@
+package oss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// 常用的错误分类, 配合errors.Is使用
+var (
+	ErrNotFound           = errors.New("not found")
+	ErrAccessDenied       = errors.New("access denied")
+	ErrRetryable          = errors.New("retryable")
+	ErrPreconditionFailed = errors.New("precondition failed")
+)
+
+// Error 对象存储返回的错误(S3的XML错误格式)
+type Error struct {
+	XMLName         xml.Name `xml:"Error"`
+	StatusCode      int      `xml:"-"`         // http状态
+	Code            string   `xml:"Code"`      // 错误码, 例如NoSuchKey, AccessDenied, SignatureDoesNotMatch
+	Message         string   `xml:"Message"`   // 错误描述
+	RequestId       string   `xml:"RequestId"` // 请求ID
+	HostId          string   `xml:"HostId"`    // 主机ID
+	Resource        string   `xml:"Resource"`  // 请求资源
+	HeaderRequestId string   `xml:"-"`         // 云厂请求ID头, 例如x-amz-request-id
+	Body            string   `xml:"-"`         // 无法解析XML时的原始内容
+}
+
+func (e *Error) Error() string {
+	requestId := If(e.RequestId != "", e.RequestId, e.HeaderRequestId)
+	if e.Code == "" {
+		return fmt.Sprintf("invalid status(%v): %s (request id: %s)", e.StatusCode, e.Body, requestId)
+	}
+	return fmt.Sprintf("invalid status(%v): %s: %s (request id: %s)", e.StatusCode, e.Code, e.Message, requestId)
+}
+
+// Is 支持errors.Is(err, ErrNotFound)等分类判断
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		switch e.Code {
+		case "NoSuchKey", "NoSuchBucket", "NoSuchUpload", "NotFound":
+			return true
+		}
+		return e.StatusCode == http.StatusNotFound
+	case ErrAccessDenied:
+		return e.Code == "AccessDenied" || e.StatusCode == http.StatusForbidden
+	case ErrPreconditionFailed:
+		return e.Code == "PreconditionFailed" || e.StatusCode == http.StatusPreconditionFailed
+	case ErrRetryable:
+		if retryableCode(e.Code) {
+			return true
+		}
+		switch e.StatusCode {
+		case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway,
+			http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
+
+func retryableCode(code string) bool {
+	switch code {
+	case "SlowDown", "InternalError", "ServiceUnavailable", "RequestTimeout", "Throttling":
+		return true
+	}
+	return false
+}
+
+func IsNotFound(err error) bool {
+	return errors.Is(err, ErrNotFound)
+}
+
+func IsAccessDenied(err error) bool {
+	return errors.Is(err, ErrAccessDenied)
+}
+
+// IsPreconditionFailed 条件请求(例如If-Match)不满足, 通常表示对象已被修改
+func IsPreconditionFailed(err error) bool {
+	return errors.Is(err, ErrPreconditionFailed)
+}
+
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrRetryable)
+}
+
+/*=================================*\
+	非法状态错误
+\*=================================*/
+
+func invalidStatusError(rsp *http.Response, p *Profile) error {
+	buf := borrowBuffer()
+	defer returnBuffer(buf)
+	buf.ReadFrom(rsp.Body)
+
+	e := new(Error)
+	if buf.Len() > 0 && xml.Unmarshal(buf.Bytes(), e) != nil {
+		// 非XML内容(例如网关错误页)保留原文
+		e.Body = string(bytes.TrimSpace(buf.Bytes()))
+	}
+	e.StatusCode = rsp.StatusCode
+	if p.RequestIdHeader != "" {
+		e.HeaderRequestId = rsp.Header.Get(p.RequestIdHeader)
+	}
+	return e
+}
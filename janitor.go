@@ -0,0 +1,120 @@
+package oss
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJanitorMaxAge      = 24 * time.Hour // 默认过期时长
+	defaultJanitorConcurrency = 4              // 默认并发abort数
+)
+
+type JanitorConfig struct {
+	MaxAge      time.Duration `json:"max_age"`     // 初始化超过该时长的分片上传视为过期(默认24小时)
+	Prefix      string        `json:"prefix"`      // 在Config.Prefix下进一步限定的前缀
+	Concurrency int           `json:"concurrency"` // 并发abort数(默认4)
+	DryRun      bool          `json:"dry_run"`     // 只统计不abort
+}
+
+// Janitor 清理过期(进程崩溃等原因遗留)的分片上传, 避免持续产生存储费用
+type Janitor struct {
+	ossi        OSSI
+	maxAge      time.Duration
+	prefix      string
+	concurrency int
+	dryRun      bool
+}
+
+func NewJanitor(o OSSI, c *JanitorConfig) *Janitor {
+	if c == nil {
+		c = new(JanitorConfig)
+	}
+	return &Janitor{
+		ossi:        o,
+		maxAge:      NvlD(c.MaxAge, defaultJanitorMaxAge),
+		prefix:      c.Prefix,
+		concurrency: NvlI(c.Concurrency, defaultJanitorConcurrency),
+		dryRun:      c.DryRun,
+	}
+}
+
+// JanitorReport 清理结果汇总
+type JanitorReport struct {
+	DryRun  bool              // 是否只统计
+	Scanned int               // 列举的分片上传数
+	Stale   []*Upload         // 过期的分片上传
+	Aborted int               // 成功abort的数量
+	Failed  []*JanitorFailure // abort失败的分片上传
+}
+
+type JanitorFailure struct {
+	Upload *Upload
+	Err    error
+}
+
+func (r *JanitorReport) String() string {
+	return fmt.Sprintf("scanned: %d, stale: %d, aborted: %d, failed: %d, dry run: %v",
+		r.Scanned, len(r.Stale), r.Aborted, len(r.Failed), r.DryRun)
+}
+
+/*
+Run 列举Config.Prefix(及JanitorConfig.Prefix)下的分片上传, abort初始化时间超过MaxAge的部分.
+列举失败返回错误, 单个abort失败记录在JanitorReport.Failed.
+*/
+func (j *Janitor) Run(c context.Context) (*JanitorReport, error) {
+	report := &JanitorReport{DryRun: j.dryRun}
+	deadline := time.Now().Add(-j.maxAge)
+
+	for keyMarker, uploadIdMarker := "", ""; ; {
+		result, err := j.ossi.ListMultipartUploads(c, j.prefix, keyMarker, uploadIdMarker, 0)
+		if err != nil {
+			return report, err
+		}
+		report.Scanned += len(result.Uploads)
+		for _, u := range result.Uploads {
+			if u.Initiated.Before(deadline) {
+				report.Stale = append(report.Stale, u)
+			}
+		}
+		// 避免服务端未返回marker时重复列举
+		if !result.IsTruncated || result.NextKeyMarker == "" {
+			break
+		}
+		keyMarker, uploadIdMarker = result.NextKeyMarker, result.NextUploadIdMarker
+	}
+	if j.dryRun {
+		return report, nil
+	}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	sem := make(chan struct{}, j.concurrency)
+	for _, u := range report.Stale {
+		if c.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(u *Upload) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			err := j.ossi.AbortMultipartUpload(c, u.Key, u.UploadId)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil && !IsNotFound(err) {
+				report.Failed = append(report.Failed, &JanitorFailure{Upload: u, Err: err})
+			} else {
+				report.Aborted++
+			}
+		}(u)
+	}
+	wg.Wait()
+	return report, c.Err()
+}
@@ -0,0 +1,73 @@
+package oss
+
+import (
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJanitorRun(t *testing.T) {
+	var aborted int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete {
+			atomic.AddInt32(&aborted, 1)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		// 分二页返回: 第一页一个过期一个未过期, 第二页一个过期
+		result := ListMultipartUploadsResult{}
+		if r.URL.Query().Get("key-marker") == "" {
+			result.IsTruncated = true
+			result.NextKeyMarker, result.NextUploadIdMarker = "b", "2"
+			result.Uploads = []*Upload{
+				{Key: "a", UploadId: "1", Initiated: time.Now().Add(-48 * time.Hour)},
+				{Key: "b", UploadId: "2", Initiated: time.Now()},
+			}
+		} else {
+			result.Uploads = []*Upload{
+				{Key: "c", UploadId: "3", Initiated: time.Now().Add(-25 * time.Hour)},
+			}
+		}
+		xml.NewEncoder(w).Encode(&result)
+	}))
+	defer srv.Close()
+
+	report, err := NewJanitor(newTestOSSI(srv), &JanitorConfig{DryRun: true}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 3 || len(report.Stale) != 2 || aborted != 0 {
+		t.Fatal(report)
+	}
+
+	report, err = NewJanitor(newTestOSSI(srv), nil).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Aborted != 2 || len(report.Failed) != 0 || aborted != 2 {
+		t.Fatal(report)
+	}
+}
+
+func TestJanitorRunMissingMarker(t *testing.T) {
+	var pages int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&pages, 1)
+		// 截断但未返回marker
+		result := ListMultipartUploadsResult{IsTruncated: true}
+		result.Uploads = []*Upload{{Key: "a", UploadId: "1", Initiated: time.Now()}}
+		xml.NewEncoder(w).Encode(&result)
+	}))
+	defer srv.Close()
+
+	report, err := NewJanitor(newTestOSSI(srv), &JanitorConfig{DryRun: true}).Run(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Scanned != 1 || pages != 1 {
+		t.Fatal(report, pages)
+	}
+}
@@ -0,0 +1,166 @@
+package oss
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// 生命周期规则状态
+const (
+	LifecycleEnabled  = "Enabled"
+	LifecycleDisabled = "Disabled"
+)
+
+// 生命周期转换的通用存储类型, 提交时按Profile.StorageClassIA/StorageClassArchive转换为云厂的名称. 其他值原样提交
+const (
+	StorageClassIA      = "STANDARD_IA"
+	StorageClassArchive = "ARCHIVE"
+)
+
+// LifecycleConfiguration 桶的生命周期配置(S3的?lifecycle格式)
+type LifecycleConfiguration struct {
+	XMLName xml.Name         `xml:"LifecycleConfiguration"`
+	Rules   []*LifecycleRule `xml:"Rule"`
+}
+
+// LifecycleRule 生命周期规则. 注意: key前缀不会自动拼接Config.Prefix
+type LifecycleRule struct {
+	ID                             string                          `xml:"ID,omitempty"`
+	Status                         string                          `xml:"Status"` // Enabled或Disabled
+	Filter                         *LifecycleFilter                `xml:"Filter"` // 为nil表示作用于整个桶
+	Expiration                     *LifecycleExpiration            `xml:"Expiration,omitempty"`
+	Transitions                    []*LifecycleTransition          `xml:"Transition,omitempty"`
+	NoncurrentVersionExpiration    *NoncurrentVersionExpiration    `xml:"NoncurrentVersionExpiration,omitempty"`
+	NoncurrentVersionTransitions   []*NoncurrentVersionTransition  `xml:"NoncurrentVersionTransition,omitempty"`
+	AbortIncompleteMultipartUpload *AbortIncompleteMultipartUpload `xml:"AbortIncompleteMultipartUpload,omitempty"`
+}
+
+// LifecycleFilter 规则的作用范围, 同时指定多个条件时使用And
+type LifecycleFilter struct {
+	Prefix string        `xml:"Prefix,omitempty"`
+	Tag    *Tag          `xml:"Tag,omitempty"`
+	And    *LifecycleAnd `xml:"And,omitempty"`
+}
+
+type LifecycleAnd struct {
+	Prefix string `xml:"Prefix,omitempty"`
+	Tags   []*Tag `xml:"Tag,omitempty"`
+}
+
+// LifecycleExpiration 过期删除, Days与Date二选一. Date须为UTC零点
+type LifecycleExpiration struct {
+	Days                      int        `xml:"Days,omitempty"`
+	Date                      *time.Time `xml:"Date,omitempty"`
+	ExpiredObjectDeleteMarker bool       `xml:"ExpiredObjectDeleteMarker,omitempty"` // 删除过期的删除标记(多版本)
+}
+
+// LifecycleTransition 转换存储类型, Days与Date二选一
+type LifecycleTransition struct {
+	Days         int        `xml:"Days,omitempty"`
+	Date         *time.Time `xml:"Date,omitempty"`
+	StorageClass string     `xml:"StorageClass"` // StorageClassIA, StorageClassArchive或云厂的存储类型名称
+}
+
+// NoncurrentVersionExpiration 历史版本成为非当前版本指定天数后删除
+type NoncurrentVersionExpiration struct {
+	NoncurrentDays int `xml:"NoncurrentDays"`
+}
+
+type NoncurrentVersionTransition struct {
+	NoncurrentDays int    `xml:"NoncurrentDays"`
+	StorageClass   string `xml:"StorageClass"`
+}
+
+// AbortIncompleteMultipartUpload 分片上传初始化指定天数后未完成则中止
+type AbortIncompleteMultipartUpload struct {
+	DaysAfterInitiation int `xml:"DaysAfterInitiation"`
+}
+
+/*
+PutBucketLifecycle 设置桶的生命周期规则(覆盖原有规则)
+*/
+func (b *bucketAdminImpl) PutBucketLifecycle(ctx context.Context, bucket string, config *LifecycleConfiguration) error {
+	body, err := LifecycleContent(config, b.profile)
+	if err != nil {
+		return err
+	}
+	return b.putBucketContent(ctx, body, func(s BucketStorage, contentMD5 string) *RequestSetting {
+		return s.PutBucketLifecycle(bucket, contentMD5)
+	})
+}
+
+// GetBucketLifecycle 未设置规则时返回ErrNotFound(NoSuchLifecycleConfiguration)
+func (b *bucketAdminImpl) GetBucketLifecycle(ctx context.Context, bucket string) (*LifecycleConfiguration, error) {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).GetBucketLifecycle(bucket) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer discardResponseBody(rsp)
+
+	return ExtractLifecycleConfiguration(rsp)
+}
+
+func (b *bucketAdminImpl) DeleteBucketLifecycle(ctx context.Context, bucket string) error {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).DeleteBucketLifecycle(bucket) },
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+/*
+LifecycleContent 生成?lifecycle请求内容, 通用存储类型按profile转换为云厂的名称(不修改config)
+*/
+func LifecycleContent(config *LifecycleConfiguration, p *Profile) ([]byte, error) {
+	content := &LifecycleConfiguration{Rules: make([]*LifecycleRule, len(config.Rules))}
+	for i, r := range config.Rules {
+		rule := *r
+		if rule.Filter == nil {
+			// 未指定Filter时须提交空的<Filter/>
+			rule.Filter = new(LifecycleFilter)
+		}
+		rule.Transitions = make([]*LifecycleTransition, len(r.Transitions))
+		for j, t := range r.Transitions {
+			transition := *t
+			transition.StorageClass = storageClass(t.StorageClass, p)
+			rule.Transitions[j] = &transition
+		}
+		rule.NoncurrentVersionTransitions = make([]*NoncurrentVersionTransition, len(r.NoncurrentVersionTransitions))
+		for j, t := range r.NoncurrentVersionTransitions {
+			transition := *t
+			transition.StorageClass = storageClass(t.StorageClass, p)
+			rule.NoncurrentVersionTransitions[j] = &transition
+		}
+		content.Rules[i] = &rule
+	}
+	return xml.Marshal(content)
+}
+
+func ExtractLifecycleConfiguration(rsp *http.Response) (*LifecycleConfiguration, error) {
+
+	result := new(LifecycleConfiguration)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// storageClass 通用存储类型转换为云厂的名称
+func storageClass(class string, p *Profile) string {
+	switch {
+	case class == StorageClassIA && p.StorageClassIA != "":
+		return p.StorageClassIA
+	case class == StorageClassArchive && p.StorageClassArchive != "":
+		return p.StorageClassArchive
+	}
+	return class
+}
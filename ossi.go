@@ -3,9 +3,14 @@ package oss
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
+	"sync"
 )
 
 /*================================*\
@@ -13,301 +18,678 @@ import (
 \*================================*/
 
 type OSSI interface {
-	DeleteObject(ctx context.Context, ossKey string) error
-	HasObject(ctx context.Context, ossKey string) (bool, error)
-	GetObject(ctx context.Context, ossKey string, _range *Range) (int64, io.ReadCloser, error)
-	GetObjectLink(ctx context.Context, ossKey string, expires int64) string
-	PutObjectData(ctx context.Context, ossKey string, data []byte) error
-	PutObject(ctx context.Context, ossKey string, contentLength int64, content io.Reader) error
-	InitiateMultipartUpload(c context.Context, ossKey string) (string, error)
+	DeleteObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) error
+	DeleteObjects(ctx context.Context, ossKeys []string, quiet bool) ([]*DeleteObjectResult, error)
+	HasObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) (bool, error)
+	HeadObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) (*ObjectInfo, error)
+	GetObject(ctx context.Context, ossKey string, _range *Range, opts ...*ObjectOptions) (int64, io.ReadCloser, error)
+	GetObjectLink(ctx context.Context, ossKey string, expires int64) (string, error)
+	PresignURL(ctx context.Context, method string, ossKey string, expires int64, opts ...*PresignOptions) (string, error)
+	PostPolicy(ctx context.Context, policy *PostPolicy) (*PostForm, error)
+	PutObjectData(ctx context.Context, ossKey string, data []byte, opts ...*PutOptions) error
+	PutObject(ctx context.Context, ossKey string, contentLength int64, content io.Reader, opts ...*PutOptions) error
+	InitiateMultipartUpload(c context.Context, ossKey string, opts ...*PutOptions) (string, error)
 	UploadPart(c context.Context, ossKey string, uploadId string, partNumber int, data []byte) (string, error)
 	AbortMultipartUpload(c context.Context, ossKey string, uploadId string) error
 	CompleteMultipartUpload(c context.Context, ossKey string, uploadId string, parts []*Part) error
+	CopyObject(ctx context.Context, srcKey string, dstKey string, opts ...*CopyOptions) (*CopyResult, error)
+	UploadPartCopy(c context.Context, srcKey string, dstKey string, uploadId string, partNumber int, _range *Range, opts ...*ObjectOptions) (string, error)
+	ListParts(c context.Context, ossKey string, uploadId string, partNumberMarker int, maxParts int) (*ListPartsResult, error)
+	ListMultipartUploads(c context.Context, prefix string, keyMarker string, uploadIdMarker string, maxUploads int) (*ListMultipartUploadsResult, error)
+	ListObjects(ctx context.Context, prefix string, delimiter string, continuationToken string, maxKeys int) (*ListObjectsResult, error)
+	ListObjectVersions(ctx context.Context, prefix string, delimiter string, keyMarker string, versionIdMarker string, maxKeys int) (*ListObjectVersionsResult, error)
+	PutObjectTagging(ctx context.Context, ossKey string, tags []*Tag, opts ...*ObjectOptions) error
+	GetObjectTagging(ctx context.Context, ossKey string, opts ...*ObjectOptions) ([]*Tag, error)
+	DeleteObjectTagging(ctx context.Context, ossKey string, opts ...*ObjectOptions) error
 }
 
 type ossiImpl struct {
 	use     string
+	prefix  string
+	profile *Profile
 	storage Storage
 	client  *http.Client
+	retry   *retryer
+	hash    func(data []byte) string // 上传内容hash, 为nil时不要求服务端校验
+	creds   CredentialsProvider      // 凭证提供者(已缓存), 为nil时使用StorageConfig的静态凭证
 }
 
-func New(use string, config *Config) OSSI {
+/*
+New 创建OSSI, use为云厂(KS3, OBS, AWS, MINIO, OSS, COS). 云厂不支持Config.Signature时返回错误
+*/
+func New(use string, config *Config) (OSSI, error) {
+	profile, ok := profiles[use]
+	if !ok {
+		return nil, fmt.Errorf("unknown profile %q", use)
+	}
+	signature, ok := signatures[config.Signature]
+	if !ok {
+		return nil, fmt.Errorf("%s: unknown signature %q", use, config.Signature)
+	}
+	storage, err := signature(config.Prefix, &config.StorageConfig, profile)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", use, err)
+	}
 	if config.ContentType == "" {
 		// 设置默认内容类型为二进制流
 		config.ContentType = contentTypeApplicationOctetStream
 	}
+	var creds CredentialsProvider
+	if config.Credentials != nil {
+		// 缓存凭证直到过期, 避免每次请求都访问provider
+		creds = NewCredentialsCache(config.Credentials)
+	}
 	return &ossiImpl{
 		use:     use,
-		storage: signatures[config.Signature](config.Prefix, &config.StorageConfig, profiles[use]),
+		prefix:  config.Prefix,
+		profile: profile,
+		storage: storage,
 		client:  NewClient(&config.ClientConfig),
+		retry:   newRetryer(&config.RetryConfig),
+		hash:    payloadHash(config),
+		creds:   creds,
+	}, nil
+}
+
+// payloadHash 根据签名版本选择上传内容的hash. V4签名内容SHA256, V2/V5使用Content-MD5
+func payloadHash(config *Config) func(data []byte) string {
+	if !config.PayloadSigning {
+		return nil
+	}
+	if config.Signature == V4 {
+		return func(data []byte) string { return hex.EncodeToString(Sha256(data)) }
 	}
+	return func(data []byte) string { return base64.StdEncoding.EncodeToString(Md5(data)) }
+}
+
+// payload 计算上传内容的hash, 未开启PayloadSigning时返回空
+func (o *ossiImpl) payload(data []byte) string {
+	if o.hash == nil {
+		return ""
+	}
+	return o.hash(data)
 }
 
 /*
 DeleteObject 从oss删除对象
 */
-func (o *ossiImpl) DeleteObject(ctx context.Context, ossKey string) error {
-	set := o.storage.DeleteObject(ossKey)
-	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, nil)
+func (o *ossiImpl) DeleteObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) error {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.DeleteObject(ossKey, opt) },
+	})
 	if err != nil {
 		return err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
+	discardResponseBody(rsp)
+	return nil
+}
+
+const (
+	deleteObjectsBatchSize   = 1000 // 每次批量删除最多1000个key
+	deleteObjectsConcurrency = 4    // 批量删除的并发数
+)
+
+// DeleteObjectResult 批量删除中单个key的结果, Err为nil表示删除成功
+type DeleteObjectResult struct {
+	Key string
+	Err error
+}
+
+/*
+DeleteObjects 批量删除对象, 按1000个key分批并发删除, 返回与ossKeys顺序一致的结果.
+quiet模式下服务端只返回失败的key, 其余视为成功. 整批请求失败时该批key的Err为请求错误, 并返回第一个请求错误.
+*/
+func (o *ossiImpl) DeleteObjects(ctx context.Context, ossKeys []string, quiet bool) ([]*DeleteObjectResult, error) {
+	results := make([]*DeleteObjectResult, len(ossKeys))
+	for i, k := range ossKeys {
+		results[i] = &DeleteObjectResult{Key: k}
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	sem := make(chan struct{}, deleteObjectsConcurrency)
+	for start := 0; start < len(results); start += deleteObjectsBatchSize {
+		end := start + deleteObjectsBatchSize
+		if end > len(results) {
+			end = len(results)
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(batch []*DeleteObjectResult) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := o.deleteObjects(ctx, batch, quiet); err != nil {
+				for _, r := range batch {
+					r.Err = err
+				}
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+			}
+		}(results[start:end])
+	}
+	wg.Wait()
+	return results, firstErr
+}
+
+func (o *ossiImpl) deleteObjects(ctx context.Context, batch []*DeleteObjectResult, quiet bool) error {
+	keys := make([]string, len(batch))
+	for i, r := range batch {
+		keys[i] = o.prefix + r.Key
 	}
-	req.ContentLength = 0
 
-	rsp, err := o.client.Do(req)
+	buf := borrowBuffer()
+	defer returnBuffer(buf)
+
+	err := DeleteObjectsKeys(buf, keys, quiet)
+	if err != nil {
+		return err
+	}
+	contentMD5 := base64.StdEncoding.EncodeToString(Md5(buf.Bytes()))
+
+	rsp, err := o.do(ctx, &request{
+		setting:       func(s Storage) *RequestSetting { return s.DeleteObjects(contentMD5) },
+		body:          bytes.NewReader(buf.Bytes()),
+		contentLength: int64(buf.Len()),
+	})
 	if err != nil {
 		return err
 	}
 	defer discardResponseBody(rsp)
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return invalidStatusError(rsp)
+
+	result, err := ExtractDeleteResult(rsp)
+	if err != nil {
+		return err
+	}
+	failed := make(map[string]*DeleteError, len(result.Errors))
+	for _, e := range result.Errors {
+		failed[strings.TrimPrefix(e.Key, o.prefix)] = e
+	}
+	for _, r := range batch {
+		if e, ok := failed[r.Key]; ok {
+			r.Err = &Error{StatusCode: rsp.StatusCode, Code: e.Code, Message: e.Message, Resource: e.Key}
+		}
 	}
 	return nil
 }
 
-func (o *ossiImpl) HasObject(ctx context.Context, ossKey string) (bool, error) {
-	set := o.storage.HeadObject(ossKey)
-	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, nil)
+func (o *ossiImpl) HasObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) (bool, error) {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.HeadObject(ossKey, opt) },
+	})
 	if err != nil {
+		if IsNotFound(err) {
+			return false, nil
+		}
 		return false, err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
-	}
-	req.ContentLength = 0
+	discardResponseBody(rsp)
+	return true, nil
+}
 
-	rsp, err := o.client.Do(req)
+/*
+HeadObject 获取对象元数据, 对象不存在时IsNotFound(err)为true. 开启多版本时ObjectInfo.VersionId为对象的版本ID
+*/
+func (o *ossiImpl) HeadObject(ctx context.Context, ossKey string, opts ...*ObjectOptions) (*ObjectInfo, error) {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.HeadObject(ossKey, opt) },
+	})
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 	defer discardResponseBody(rsp)
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != http.StatusNotFound && rsp.StatusCode != set.Status {
-		return false, invalidStatusError(rsp)
-	}
-	return rsp.StatusCode == http.StatusOK, nil
+
+	return ExtractObjectInfo(rsp, o.profile)
 }
 
 /*
 GetObject 下载对象(或部分)
 */
-func (o *ossiImpl) GetObject(ctx context.Context, ossKey string, _range *Range) (int64, io.ReadCloser, error) {
-	set := o.storage.GetObject(ossKey, _range)
-	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, nil)
+func (o *ossiImpl) GetObject(ctx context.Context, ossKey string, _range *Range, opts ...*ObjectOptions) (int64, io.ReadCloser, error) {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.GetObject(ossKey, _range, opt) },
+	})
 	if err != nil {
 		return 0, nil, err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
-	}
-	req.ContentLength = 0
+	return rsp.ContentLength, rsp.Body, nil
+}
 
-	rsp, err := o.client.Do(req)
+/*
+GetObjectLink 生成下载外链, 有效期expires秒. 无法获取凭证时返回错误, 不生成未签名的外链
+*/
+func (o *ossiImpl) GetObjectLink(ctx context.Context, ossKey string, expires int64) (string, error) {
+	s, err := o.current()
 	if err != nil {
-		return 0, nil, err
-	}
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		defer discardResponseBody(rsp)
-		return 0, nil, invalidStatusError(rsp)
+		return "", err
 	}
+	return s.GetObjectLink(ossKey, expires), nil
+}
 
-	return rsp.ContentLength, rsp.Body, nil
+/*
+PresignURL 生成预签名url, 供浏览器或移动端直接访问对象. method支持GET/PUT/HEAD/DELETE,
+PUT可通过opts绑定Content-Type/Content-MD5, 或者指定UploadId/PartNumber预签名UploadPart
+*/
+func (o *ossiImpl) PresignURL(ctx context.Context, method string, ossKey string, expires int64, opts ...*PresignOptions) (string, error) {
+	switch method {
+	case http.MethodGet, http.MethodPut, http.MethodHead, http.MethodDelete:
+	default:
+		return "", fmt.Errorf("presign: unsupported method %q", method)
+	}
+	opt := firstOption(opts)
+	if opt != nil && opt.UploadId != "" {
+		// 预签名UploadPart只能PUT, 且必须指定有效的分片号
+		if method != http.MethodPut {
+			return "", fmt.Errorf("presign: upload part requires PUT, got %q", method)
+		}
+		if opt.PartNumber < 1 {
+			return "", fmt.Errorf("presign: invalid part number %d", opt.PartNumber)
+		}
+	}
+	s, err := o.current()
+	if err != nil {
+		return "", err
+	}
+	return s.PresignURL(method, ossKey, expires, opt), nil
 }
 
-func (o *ossiImpl) GetObjectLink(ctx context.Context, ossKey string, expires int64) string {
-	return o.storage.GetObjectLink(ossKey, expires)
+/*
+PostPolicy 生成浏览器表单上传(POST Object)的url及字段, 表单按字段提交后最后附加file字段
+*/
+func (o *ossiImpl) PostPolicy(ctx context.Context, policy *PostPolicy) (*PostForm, error) {
+	if policy == nil {
+		return nil, errors.New("post policy: nil policy")
+	}
+	s, err := o.current()
+	if err != nil {
+		return nil, err
+	}
+	return s.PostPolicy(policy), nil
 }
 
 /*
 PutObjectData 上传对象数据
 */
-func (o *ossiImpl) PutObjectData(ctx context.Context, ossKey string, data []byte) error {
-	set := o.storage.PutObject(ossKey, "") // 不要求服务端hash校验
-	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, bytes.NewReader(data))
+func (o *ossiImpl) PutObjectData(ctx context.Context, ossKey string, data []byte, opts ...*PutOptions) error {
+	hash := o.payload(data)
+	rsp, err := o.do(ctx, &request{
+		setting:       func(s Storage) *RequestSetting { return s.PutObject(ossKey, hash, firstOption(opts)) },
+		body:          bytes.NewReader(data),
+		contentLength: int64(len(data)),
+	})
 	if err != nil {
 		return err
 	}
-	for k, v := range set.Header {
-		// 注意:使用Header.Set()会将header name标准化
-		req.Header[k] = []string{v}
-	}
-	req.ContentLength = int64(len(data))
+	discardResponseBody(rsp)
+	return nil
+}
 
-	rsp, err := o.client.Do(req)
+/*
+PutObject 上传对象. 注意: content实现io.Seeker才能重试.
+开启PayloadSigning且支持流式签名(aws-chunked)时逐块签名上传; 流式签名需要原始内容长度, 长度未知时改用分片上传.
+*/
+func (o *ossiImpl) PutObject(ctx context.Context, ossKey string, contentLength int64, content io.Reader, opts ...*PutOptions) error {
+	setting := func(s Storage) *RequestSetting { return s.PutObject(ossKey, "", firstOption(opts)) } // 不要求服务端hash校验
+	if _, ok := o.storage.(StreamingStorage); ok && o.hash != nil && o.profile.StreamingPayload != "" {
+		if contentLength < 0 {
+			return NewUploader(o, nil).Upload(ctx, ossKey, content, opts...)
+		}
+		setting = func(s Storage) *RequestSetting {
+			return s.(StreamingStorage).PutObjectStreaming(ossKey, contentLength, firstOption(opts))
+		}
+	}
+	rsp, err := o.do(ctx, &request{
+		setting:       setting,
+		body:          content,
+		contentLength: contentLength,
+	})
 	if err != nil {
 		return err
 	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+func (o *ossiImpl) InitiateMultipartUpload(c context.Context, ossKey string, opts ...*PutOptions) (string, error) {
+	rsp, err := o.do(c, &request{
+		setting:       func(s Storage) *RequestSetting { return s.InitiateMultipartUpload(ossKey, firstOption(opts)) },
+		nonIdempotent: true,
+	})
+	if err != nil {
+		return "", err
+	}
 	defer discardResponseBody(rsp)
 
-	// 断言状态
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return invalidStatusError(rsp)
+	return ExtractMultipartUploadId(rsp)
+}
+
+func (o *ossiImpl) UploadPart(c context.Context, ossKey string, uploadId string, partNumber int, data []byte) (string, error) {
+	hash := o.payload(data)
+	rsp, err := o.do(c, &request{
+		setting:       func(s Storage) *RequestSetting { return s.UploadPart(ossKey, uploadId, partNumber, hash) },
+		body:          bytes.NewReader(data),
+		contentLength: int64(len(data)),
+	})
+	if err != nil {
+		return "", err
 	}
-	return nil
+	defer discardResponseBody(rsp)
+
+	return ExtractMultipartUploadETag(rsp)
 }
 
-/*
-PutObject 上传对象
-*/
-func (o *ossiImpl) PutObject(ctx context.Context, ossKey string, contentLength int64, content io.Reader) error {
-	set := o.storage.PutObject(ossKey, "") // 不要求服务端hash校验
-	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, content)
+func (o *ossiImpl) AbortMultipartUpload(c context.Context, ossKey string, uploadId string) error {
+	rsp, err := o.do(c, &request{
+		setting: func(s Storage) *RequestSetting { return s.AbortMultipartUpload(ossKey, uploadId) },
+	})
 	if err != nil {
 		return err
 	}
-	for k, v := range set.Header {
-		// 注意:使用Header.Set()会将header name标准化
-		req.Header[k] = []string{v}
-	}
-	if contentLength < 0 {
-		// 采用chunked方式上传
-		req.Header[TransferEncoding] = TransferEncodingChunked
-	} else {
-		req.ContentLength = contentLength
-	}
+	discardResponseBody(rsp)
+	return nil
+}
 
-	rsp, err := o.client.Do(req)
+func (o *ossiImpl) CompleteMultipartUpload(c context.Context, ossKey string, uploadId string, parts []*Part) error {
+	buf := borrowBuffer()
+	defer returnBuffer(buf)
+
+	err := CompleteMultipartUploadParts(buf, parts)
 	if err != nil {
 		return err
 	}
-	defer discardResponseBody(rsp)
 
-	// 断言状态
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return invalidStatusError(rsp)
+	rsp, err := o.do(c, &request{
+		setting:       func(s Storage) *RequestSetting { return s.CompleteMultipartUpload(ossKey, uploadId) },
+		body:          bytes.NewReader(buf.Bytes()),
+		contentLength: int64(buf.Len()),
+		nonIdempotent: true,
+	})
+	if err != nil {
+		return err
 	}
+	discardResponseBody(rsp)
 	return nil
 }
 
-func (o *ossiImpl) InitiateMultipartUpload(c context.Context, ossKey string) (string, error) {
-	set := o.storage.InitiateMultipartUpload(ossKey)
-	req, err := http.NewRequestWithContext(c, set.Method, set.Url, nil)
+/*
+CopyObject 服务端复制对象(不超过5G), 更大的对象使用UploadPartCopy
+*/
+func (o *ossiImpl) CopyObject(ctx context.Context, srcKey string, dstKey string, opts ...*CopyOptions) (*CopyResult, error) {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.CopyObject(srcKey, dstKey, opt) },
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
-	}
-	req.ContentLength = 0
+	defer discardResponseBody(rsp)
 
-	rsp, err := o.client.Do(req)
+	return ExtractCopyResult(rsp, o.profile)
+}
+
+// UploadPartCopy 复制源对象的范围作为分片, opts可指定源对象的版本ID
+func (o *ossiImpl) UploadPartCopy(c context.Context, srcKey string, dstKey string, uploadId string, partNumber int, _range *Range, opts ...*ObjectOptions) (string, error) {
+	var srcVersionId string
+	if opt := firstOption(opts); opt != nil {
+		srcVersionId = opt.VersionId
+	}
+	rsp, err := o.do(c, &request{
+		setting: func(s Storage) *RequestSetting {
+			return s.UploadPartCopy(srcKey, srcVersionId, dstKey, uploadId, partNumber, _range)
+		},
+	})
 	if err != nil {
 		return "", err
 	}
 	defer discardResponseBody(rsp)
 
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return "", invalidStatusError(rsp)
-	}
-	uploadId, err := ExtractMultipartUploadId(rsp)
+	result, err := ExtractCopyResult(rsp, o.profile)
 	if err != nil {
 		return "", err
 	}
-	return uploadId, nil
+	return result.ETag, nil
 }
 
-func (o *ossiImpl) UploadPart(c context.Context, ossKey string, uploadId string, partNumber int, data []byte) (string, error) {
-
-	set := o.storage.UploadPart(ossKey, uploadId, partNumber, "")
-	req, err := http.NewRequestWithContext(c, set.Method, set.Url, bytes.NewReader(data))
+func (o *ossiImpl) ListParts(c context.Context, ossKey string, uploadId string, partNumberMarker int, maxParts int) (*ListPartsResult, error) {
+	rsp, err := o.do(c, &request{
+		setting: func(s Storage) *RequestSetting { return s.ListParts(ossKey, uploadId, partNumberMarker, maxParts) },
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
+	defer discardResponseBody(rsp)
+
+	result, err := ExtractListPartsResult(rsp)
+	if err != nil {
+		return nil, err
 	}
-	req.ContentLength = int64(len(data))
+	result.Key = strings.TrimPrefix(result.Key, o.prefix)
+	return result, nil
+}
 
-	rsp, err := o.client.Do(req)
+/*
+ListMultipartUploads 列举未完成(未complete或abort)的分片上传, 返回的key已去除Config.Prefix
+*/
+func (o *ossiImpl) ListMultipartUploads(c context.Context, prefix string, keyMarker string, uploadIdMarker string, maxUploads int) (*ListMultipartUploadsResult, error) {
+	rsp, err := o.do(c, &request{
+		setting: func(s Storage) *RequestSetting {
+			return s.ListMultipartUploads(prefix, keyMarker, uploadIdMarker, maxUploads)
+		},
+	})
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	defer discardResponseBody(rsp)
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return "", invalidStatusError(rsp)
+
+	result, err := ExtractListMultipartUploadsResult(rsp)
+	if err != nil {
+		return nil, err
 	}
 
-	return ExtractMultipartUploadETag(rsp)
+	// 去除Config.Prefix
+	result.Prefix = strings.TrimPrefix(result.Prefix, o.prefix)
+	result.KeyMarker = strings.TrimPrefix(result.KeyMarker, o.prefix)
+	result.NextKeyMarker = strings.TrimPrefix(result.NextKeyMarker, o.prefix)
+	for _, v := range result.Uploads {
+		v.Key = strings.TrimPrefix(v.Key, o.prefix)
+	}
+	return result, nil
 }
 
-func (o *ossiImpl) AbortMultipartUpload(c context.Context, ossKey string, uploadId string) error {
+/*
+ListObjects 列举对象, 返回的key已去除Config.Prefix. 下一页的continuationToken为NextContinuationToken,
+marker分页(如COS)为NextMarker
+*/
+func (o *ossiImpl) ListObjects(ctx context.Context, prefix string, delimiter string, continuationToken string, maxKeys int) (*ListObjectsResult, error) {
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.ListObjects(prefix, delimiter, continuationToken, maxKeys) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer discardResponseBody(rsp)
 
-	set := o.storage.AbortMultipartUpload(ossKey, uploadId)
-	req, err := http.NewRequestWithContext(c, set.Method, set.Url, nil)
+	result, err := ExtractListObjectsResult(rsp)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	for k, v := range set.Header {
-		req.Header[k] = []string{v}
+
+	// marker分页(如COS)没有NextContinuationToken, 未返回NextMarker时以最后一个key代替
+	if result.IsTruncated && result.NextContinuationToken == "" && result.NextMarker == "" && len(result.Objects) > 0 {
+		result.NextMarker = result.Objects[len(result.Objects)-1].Key
 	}
-	req.ContentLength = 0
 
-	rsp, err := o.client.Do(req)
+	// 去除Config.Prefix
+	result.Prefix = strings.TrimPrefix(result.Prefix, o.prefix)
+	for _, v := range result.Objects {
+		v.Key = strings.TrimPrefix(v.Key, o.prefix)
+	}
+	for i, v := range result.CommonPrefixes {
+		result.CommonPrefixes[i] = strings.TrimPrefix(v, o.prefix)
+	}
+	return result, nil
+}
+
+/*
+ListObjectVersions 列举对象版本及删除标记, 返回的key已去除Config.Prefix. 遍历全部版本可使用ObjectVersionIterator
+*/
+func (o *ossiImpl) ListObjectVersions(ctx context.Context, prefix string, delimiter string, keyMarker string, versionIdMarker string, maxKeys int) (*ListObjectVersionsResult, error) {
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting {
+			return s.ListObjectVersions(prefix, delimiter, keyMarker, versionIdMarker, maxKeys)
+		},
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer discardResponseBody(rsp)
 
-	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return invalidStatusError(rsp)
+	result, err := ExtractListObjectVersionsResult(rsp)
+	if err != nil {
+		return nil, err
 	}
-	return nil
+
+	// 去除Config.Prefix
+	result.Prefix = strings.TrimPrefix(result.Prefix, o.prefix)
+	result.KeyMarker = strings.TrimPrefix(result.KeyMarker, o.prefix)
+	result.NextKeyMarker = strings.TrimPrefix(result.NextKeyMarker, o.prefix)
+	for _, v := range result.Versions {
+		v.Key = strings.TrimPrefix(v.Key, o.prefix)
+	}
+	for i, v := range result.CommonPrefixes {
+		result.CommonPrefixes[i] = strings.TrimPrefix(v, o.prefix)
+	}
+	return result, nil
 }
 
-func (o *ossiImpl) CompleteMultipartUpload(c context.Context, ossKey string, uploadId string, parts []*Part) error {
+/*=================================*\
+	请求执行(含重试)
+\*=================================*/
 
-	set := o.storage.CompleteMultipartUpload(ossKey, uploadId)
+// request 一次oss请求. setting在每次尝试时调用, 保证重试时使用当前凭证重新签名
+type request struct {
+	setting       func(s Storage) *RequestSetting
+	body          io.Reader // 请求内容, nil表示无内容
+	contentLength int64     // 请求内容长度, 小于0采用chunked方式上传
+	nonIdempotent bool      // 非幂等请求(发起/完成分片上传), 只在请求未发出时重试
+}
 
-	buf := borrowBuffer()
-	defer returnBuffer(buf)
+/*
+do 执行请求, 返回状态符合预期的响应(调用方负责关闭). 可重试的错误按退避策略重试,
+body必须实现io.Seeker才能重试, 否则只尝试一次.
+*/
+func (o *ossiImpl) do(ctx context.Context, r *request) (*http.Response, error) {
+	var offset int64
+	seeker, rewindable := r.body.(io.Seeker)
+	if r.body == nil {
+		rewindable = true
+	} else if rewindable {
+		var err error
+		if offset, err = seeker.Seek(0, io.SeekCurrent); err != nil {
+			rewindable = false
+		}
+	}
 
-	err := CompleteMultipartUploadParts(buf, parts)
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && seeker != nil {
+			if _, err := seeker.Seek(offset, io.SeekStart); err != nil {
+				return nil, err
+			}
+		}
+		rsp, err := o.send(ctx, r)
+		if err == nil {
+			return rsp, nil
+		}
+		if !rewindable || attempt >= o.retry.maxAttempts || !o.retry.retryable(ctx, err) || r.nonIdempotent && !unsent(err) {
+			return nil, err
+		}
+		if err := o.retry.backoff(ctx, attempt); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// current 本次签名使用的storage. 设置了凭证provider时获取一次凭证并绑定, 签名全程使用同一凭证
+func (o *ossiImpl) current() (Storage, error) {
+	if o.creds == nil {
+		return o.storage, nil
+	}
+	cred, err := o.creds.Retrieve()
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return o.storage.(credentialsBinder).withCredentials(cred), nil
+}
 
-	req, err := http.NewRequestWithContext(c, set.Method, set.Url, buf)
+func (o *ossiImpl) send(ctx context.Context, r *request) (*http.Response, error) {
+	s, err := o.current()
 	if err != nil {
-		return err
+		return nil, err
+	}
+	set := r.setting(s)
+
+	var body io.Reader
+	contentLength := r.contentLength
+	if set.Chunk != nil {
+		// 流式签名: 每次尝试使用新的seed signature重新编码(结束分块使空内容也有body)
+		src := r.body
+		if src == nil {
+			src = http.NoBody
+		}
+		body = io.NopCloser(newChunkReader(src, r.contentLength, set.Chunk))
+		contentLength = set.Chunk.EncodedLength(r.contentLength)
+	} else if r.body != nil && r.contentLength != 0 {
+		// 避免http.Client关闭调用方的body(重试需要重用)
+		body = io.NopCloser(r.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, set.Method, set.Url, body)
+	if err != nil {
+		return nil, err
 	}
 	for k, v := range set.Header {
+		// 注意:使用Header.Set()会将header name标准化
 		req.Header[k] = []string{v}
 	}
-	req.ContentLength = int64(buf.Len())
+	if contentLength < 0 {
+		// 采用chunked方式上传
+		req.Header[TransferEncoding] = TransferEncodingChunked
+	} else {
+		req.ContentLength = contentLength
+	}
 
 	rsp, err := o.client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer discardResponseBody(rsp)
-
+	// 断言状态
 	if rsp.StatusCode != http.StatusOK && rsp.StatusCode != set.Status {
-		return invalidStatusError(rsp)
+		defer discardResponseBody(rsp)
+		return nil, invalidStatusError(rsp, o.profile)
 	}
-	return nil
+	return rsp, nil
 }
 
-/*=================================*\
-	非法状态错误
-\*=================================*/
-
-func invalidStatusError(rsp *http.Response) error {
-	buf := borrowBuffer()
-	defer returnBuffer(buf)
-	buf.ReadFrom(rsp.Body)
-	return fmt.Errorf("invalid status(%v): %s", rsp.StatusCode, buf.Bytes())
+// firstOption 可选参数(取第一个), 未指定返回nil
+func firstOption[T any](opts []*T) *T {
+	if len(opts) > 0 {
+		return opts[0]
+	}
+	return nil
 }
 
-var discardBuffer = make([]byte, 2048)
-
 func discardResponseBody(rsp *http.Response) {
-	for {
-		n, err := rsp.Body.Read(discardBuffer)
-		if n == 0 && err != nil {
-			break
-		}
-	}
+	// 并发请求不能共用丢弃缓存
+	io.Copy(io.Discard, rsp.Body)
 	rsp.Body.Close()
 }
@@ -3,10 +3,21 @@ package oss
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
 	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 const (
@@ -17,7 +28,7 @@ const (
 var ctx = context.Background()
 var bs = []byte("this is another minus test only")
 
-var o = New(ossUse, &Config{
+var o = mustNew(ossUse, &Config{
 	Prefix:    "",
 	Signature: V4, // 注意: obs不支持v4签名算法!
 	StorageConfig: StorageConfig{
@@ -48,6 +59,47 @@ func TestPutObject(t *testing.T) {
 	t.Logf("PutObject success: %v\n", ossKey)
 }
 
+func TestPutObjectOptions(t *testing.T) {
+	put := PutOptions{
+		ContentType:        "text/plain",
+		CacheControl:       "no-cache",
+		ContentDisposition: `attachment; filename="test.txt"`,
+		Metadata:           map[string]string{"Source": "test"},
+	}
+	// 阿里云V4不声明SignedHeaders, 标准头不能加入签名
+	for _, use := range []string{AWS, OSS} {
+		p := profiles[use]
+		srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			io.Copy(io.Discard, r.Body)
+			if !verifySignatureV4(r, p, "test") {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			if r.Header.Get("Content-Type") != put.ContentType || r.Header.Get("Cache-Control") != put.CacheControl ||
+				r.Header.Get("Content-Disposition") != put.ContentDisposition || r.Header.Get(p.MetaHeaderPrefix+"source") != "test" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if r.Header.Get(p.CopySourceHeader) != "" {
+				if r.Header.Get(p.DirectiveHeader) != MetadataDirectiveReplace {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`)
+			}
+		}))
+
+		o := mustNew(use, newTestConfig(srv))
+		if err := o.PutObjectData(ctx, ossKey, bs, &put); err != nil {
+			t.Fatal(use, err)
+		}
+		if _, err := o.CopyObject(ctx, ossKey, ossKey+"-copy", &CopyOptions{MetadataDirective: MetadataDirectiveReplace, PutOptions: put}); err != nil {
+			t.Fatal(use, err)
+		}
+		srv.Close()
+	}
+}
+
 func TestGetObject(t *testing.T) {
 	ln, rc, err := o.GetObject(ctx, ossKey, nil)
 	if err != nil {
@@ -61,17 +113,95 @@ func TestGetObject(t *testing.T) {
 }
 
 func TestGetObjectLink(t *testing.T) {
-	fmt.Println(o.GetObjectLink(ctx, ossKey, 180))
+	link, err := o.GetObjectLink(ctx, ossKey, 180)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fmt.Println(link)
 }
 
 func TestHasObject(t *testing.T) {
 	fmt.Println(o.HasObject(ctx, ossKey))
 }
 
+func TestHeadObject(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodHead || r.URL.Path != "/"+ossKey || !verifySignatureV4(r, ProfileAWS, "test") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		h := w.Header()
+		h.Set("Content-Length", "31")
+		h.Set("Content-Type", "text/plain")
+		h.Set("ETag", `"etag"`)
+		h.Set("Last-Modified", "Tue, 02 Jan 2024 03:04:05 GMT")
+		h.Set("x-amz-storage-class", "STANDARD_IA")
+		h.Set("x-amz-server-side-encryption", "AES256")
+		h.Set("x-amz-version-id", "v1")
+		h.Set("x-amz-meta-owner", "test")
+	}))
+	defer srv.Close()
+
+	info, err := newTestOSSI(srv).HeadObject(ctx, ossKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ContentLength != 31 || info.ContentType != "text/plain" || info.ETag != `"etag"` ||
+		!info.LastModified.Equal(time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)) || info.StorageClass != "STANDARD_IA" ||
+		info.ServerSideEncryption != "AES256" || info.VersionId != "v1" || len(info.Metadata) != 1 || info.Metadata["owner"] != "test" {
+		t.Fatalf("%+v", info)
+	}
+}
+
 func TestDeleteObject(t *testing.T) {
 	fmt.Println(o.DeleteObject(ctx, ossKey))
 }
 
+func TestCopyObject(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodPut || !verifySignatureV4(r, ProfileAWS, "test") || r.Header.Get("x-amz-copy-source") != "/test/src%20key?versionId=v1" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("x-amz-copy-source-version-id", "v1")
+		if q.Has("uploadId") {
+			// UploadPartCopy: 指定分片及复制范围
+			if q.Get("uploadId") != "upload-id" || q.Get("partNumber") != "2" || r.Header.Get("x-amz-copy-source-range") != "bytes=0-9" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `<CopyPartResult><ETag>"part"</ETag><LastModified>2024-01-02T03:04:05.000Z</LastModified></CopyPartResult>`)
+			return
+		}
+		if r.URL.Path != "/dst" || r.Header.Get("x-amz-metadata-directive") != MetadataDirectiveReplace || r.Header.Get("Content-Type") != "text/plain" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("x-amz-version-id", "v2")
+		io.WriteString(w, `<CopyObjectResult><ETag>"etag"</ETag><LastModified>2024-01-02T03:04:05.000Z</LastModified></CopyObjectResult>`)
+	}))
+	defer srv.Close()
+
+	r := newTestOSSI(srv)
+	result, err := r.CopyObject(ctx, "src key", "dst", &CopyOptions{
+		MetadataDirective: MetadataDirectiveReplace,
+		SourceVersionId:   "v1",
+		PutOptions:        PutOptions{ContentType: "text/plain"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.XMLName.Local != "CopyObjectResult" || result.ETag != `"etag"` || result.LastModified.Year() != 2024 ||
+		result.VersionId != "v2" || result.SourceVersionId != "v1" {
+		t.Fatalf("%+v", result)
+	}
+	etag, err := r.UploadPartCopy(ctx, "src key", "dst", "upload-id", 2, &Range{Start: 0, End: 9}, &ObjectOptions{VersionId: "v1"})
+	if err != nil || etag != `"part"` {
+		t.Fatal(etag, err)
+	}
+}
+
 func TestPutObjectMultipart(t *testing.T) {
 	uploadId, err := o.InitiateMultipartUpload(ctx, ossKey)
 	if err != nil {
@@ -89,3 +219,799 @@ func TestPutObjectMultipart(t *testing.T) {
 	}
 	t.Logf("PutObjectMultipart success: %v\n", ossKey)
 }
+
+func TestListObjects(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if !verifySignatureV4(r, ProfileAWS, "test") || q.Get("list-type") != "2" || q.Get("prefix") != "app/dir/" ||
+			q.Get("delimiter") != "/" || q.Get("continuation-token") != "token" || q.Get("max-keys") != "100" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, `<ListBucketResult><Prefix>app/dir/</Prefix><Delimiter>/</Delimiter><MaxKeys>100</MaxKeys><KeyCount>2</KeyCount>`+
+			`<IsTruncated>true</IsTruncated><ContinuationToken>token</ContinuationToken><NextContinuationToken>next</NextContinuationToken>`+
+			`<Contents><Key>app/dir/a.txt</Key><Size>3</Size><ETag>"etag"</ETag><LastModified>2024-01-02T03:04:05.000Z</LastModified></Contents>`+
+			`<CommonPrefixes><Prefix>app/dir/sub/</Prefix></CommonPrefixes></ListBucketResult>`)
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Prefix = "app/"
+	result, err := mustNew(AWS, cfg).ListObjects(ctx, "dir/", "/", "token", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 返回的key及前缀已去除Config.Prefix
+	v := result.Objects[0]
+	if result.Prefix != "dir/" || !result.IsTruncated || result.NextContinuationToken != "next" || len(result.Objects) != 1 ||
+		v.Key != "dir/a.txt" || v.Size != 3 || v.ETag != `"etag"` || v.LastModified.Year() != 2024 ||
+		len(result.CommonPrefixes) != 1 || result.CommonPrefixes[0] != "dir/sub/" {
+		t.Fatalf("%+v", result)
+	}
+}
+
+func TestListMultipartUploads(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if r.Method != http.MethodGet || !verifySignatureV4(r, ProfileAWS, "test") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case q.Has("uploads"):
+			if q.Get("prefix") != "app/dir/" || q.Get("key-marker") != "app/dir/a" || q.Get("upload-id-marker") != "1" || q.Get("max-uploads") != "100" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `<ListMultipartUploadsResult><Prefix>app/dir/</Prefix><KeyMarker>app/dir/a</KeyMarker><UploadIdMarker>1</UploadIdMarker>`+
+				`<NextKeyMarker>app/dir/b</NextKeyMarker><NextUploadIdMarker>2</NextUploadIdMarker><MaxUploads>100</MaxUploads><IsTruncated>true</IsTruncated>`+
+				`<Upload><Key>app/dir/b</Key><UploadId>2</UploadId><Initiated>2024-01-02T03:04:05.000Z</Initiated><StorageClass>STANDARD</StorageClass></Upload>`+
+				`</ListMultipartUploadsResult>`)
+		case r.URL.Path == "/app/dir/b" && q.Get("uploadId") == "2":
+			if q.Get("part-number-marker") != "1" || q.Get("max-parts") != "10" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			io.WriteString(w, `<ListPartsResult><Key>app/dir/b</Key><UploadId>2</UploadId><PartNumberMarker>1</PartNumberMarker>`+
+				`<NextPartNumberMarker>3</NextPartNumberMarker><MaxParts>10</MaxParts><IsTruncated>false</IsTruncated>`+
+				`<Part><PartNumber>2</PartNumber><ETag>"p2"</ETag><Size>5</Size><LastModified>2024-01-02T03:04:05.000Z</LastModified></Part>`+
+				`<Part><PartNumber>3</PartNumber><ETag>"p3"</ETag><Size>7</Size><LastModified>2024-01-02T03:04:05.000Z</LastModified></Part>`+
+				`</ListPartsResult>`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Prefix = "app/"
+	r := mustNew(AWS, cfg)
+	result, err := r.ListMultipartUploads(ctx, "dir/", "dir/a", "1", 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 返回的key及marker已去除Config.Prefix
+	if result.Prefix != "dir/" || result.KeyMarker != "dir/a" || result.NextKeyMarker != "dir/b" || result.NextUploadIdMarker != "2" ||
+		!result.IsTruncated || len(result.Uploads) != 1 {
+		t.Fatalf("%+v", result)
+	}
+	v := result.Uploads[0]
+	if v.Key != "dir/b" || v.UploadId != "2" || v.Initiated.Year() != 2024 || v.StorageClass != "STANDARD" {
+		t.Fatalf("%+v", v)
+	}
+
+	parts, err := r.ListParts(ctx, v.Key, v.UploadId, 1, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parts.Key != "dir/b" || parts.NextPartNumberMarker != 3 || parts.IsTruncated || len(parts.Parts) != 2 || parts.Parts[1].Size != 7 {
+		t.Fatalf("%+v", parts)
+	}
+	completed := parts.CompletedParts()
+	if len(completed) != 2 || completed[0].PartNumber != 2 || completed[0].ETag != `"p2"` {
+		t.Fatalf("%+v", completed)
+	}
+}
+
+func TestInvalidStatusError(t *testing.T) {
+	rsp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Header:     http.Header{"X-Amz-Request-Id": []string{"4442587FB7D0A2F9"}},
+		Body: io.NopCloser(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<Error><Code>NoSuchKey</Code><Message>The resource you requested does not exist</Message><Resource>/mybucket/myfoto.jpg</Resource><RequestId>4442587FB7D0A2F9</RequestId></Error>`)),
+	}
+	err := invalidStatusError(rsp, ProfileAWS)
+
+	var e *Error
+	if !errors.As(err, &e) || e.Code != "NoSuchKey" || e.HeaderRequestId != "4442587FB7D0A2F9" {
+		t.Fatal(err)
+	}
+	if !IsNotFound(err) || IsAccessDenied(err) || IsRetryable(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var attempts int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if body, _ := io.ReadAll(r.Body); !bytes.Equal(body, bs) {
+			t.Errorf("attempt %d: unexpected body %q", attempts, body)
+		}
+		if attempts < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			io.WriteString(w, "<Error><Code>SlowDown</Code><Message>Please reduce your request rate.</Message></Error>")
+			return
+		}
+	}))
+	defer srv.Close()
+
+	r := newTestOSSI(srv)
+	if err := r.PutObject(ctx, ossKey, int64(len(bs)), bytes.NewReader(bs)); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 3 {
+		t.Fatalf("attempts: %d", attempts)
+	}
+}
+
+func TestRetryNonIdempotent(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		// 请求到达后断开连接, 客户端无法确定服务端是否已执行
+		conn, _, _ := w.(http.Hijacker).Hijack()
+		conn.Close()
+	}))
+	defer srv.Close()
+
+	r := newTestOSSI(srv)
+	if _, err := r.InitiateMultipartUpload(ctx, ossKey); err == nil {
+		t.Fatal("expect error")
+	}
+	if err := r.CompleteMultipartUpload(ctx, ossKey, "1", []*Part{{PartNumber: 1, ETag: "1"}}); err == nil {
+		t.Fatal("expect error")
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts: %d", attempts)
+	}
+	// 幂等请求照常重试
+	if _, err := r.HasObject(ctx, ossKey); err == nil {
+		t.Fatal("expect error")
+	}
+	if attempts != 5 {
+		t.Fatalf("attempts: %d", attempts)
+	}
+}
+
+func TestDeleteObjects(t *testing.T) {
+	var batches int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&batches, 1)
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("content-md5") != base64.StdEncoding.EncodeToString(Md5(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		var req deleteObjects
+		xml.Unmarshal(body, &req)
+		result := DeleteResult{}
+		for _, v := range req.Objects {
+			if v.Key == "locked" {
+				result.Errors = append(result.Errors, &DeleteError{Key: v.Key, Code: "AccessDenied", Message: "Access Denied"})
+			}
+		}
+		xml.NewEncoder(w).Encode(&result)
+	}))
+	defer srv.Close()
+
+	keys := make([]string, 2500)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+	keys[1234] = "locked"
+
+	results, err := newTestOSSI(srv).DeleteObjects(ctx, keys, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if batches != 3 {
+		t.Fatalf("batches: %d", batches)
+	}
+	for i, r := range results {
+		if r.Key != keys[i] || (r.Err != nil) != (i == 1234) {
+			t.Fatalf("%s: %v", r.Key, r.Err)
+		}
+	}
+	if !IsAccessDenied(results[1234].Err) {
+		t.Fatal(results[1234].Err)
+	}
+}
+
+func TestSignatureV5(t *testing.T) {
+	const secret = "cos-secret"
+	// verify 按COS文档独立重算签名
+	verify := func(r *http.Request) bool {
+		auth := make(map[string]string)
+		for _, kv := range strings.Split(r.Header.Get("authorization"), "&") {
+			k, v, _ := strings.Cut(kv, "=")
+			auth[k] = v
+		}
+		encode := func(names []string, get func(string) string) string {
+			var pairs []string
+			for _, n := range names {
+				if n != "" {
+					pairs = append(pairs, n+"="+UriEncode(get(n), true))
+				}
+			}
+			return strings.Join(pairs, "&")
+		}
+		query := make(map[string]string)
+		for k, v := range r.URL.Query() {
+			query[strings.ToLower(k)] = v[0]
+		}
+		params := encode(strings.Split(auth["q-url-param-list"], ";"), func(n string) string { return query[n] })
+		headers := encode(strings.Split(auth["q-header-list"], ";"), func(n string) string {
+			if n == headerHost {
+				return r.Host
+			}
+			return r.Header.Get(n)
+		})
+		httpString := strings.ToLower(r.Method) + "\n" + r.URL.Path + "\n" + params + "\n" + headers + "\n"
+		stringToSign := "sha1\n" + auth["q-key-time"] + "\n" + fmt.Sprintf("%x", Sha1([]byte(httpString))) + "\n"
+		signKey := fmt.Sprintf("%x", HmacSha1([]byte(secret), []byte(auth["q-key-time"])))
+		return auth["q-sign-algorithm"] == "sha1" && auth["q-ak"] == "cos-access" &&
+			auth["q-signature"] == fmt.Sprintf("%x", HmacSha1([]byte(signKey), []byte(stringToSign)))
+	}
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !verify(r) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPut:
+			io.Copy(io.Discard, r.Body)
+		case r.Method == http.MethodGet && r.URL.Query().Get("marker") == "":
+			// 未指定delimiter时COS不返回NextMarker
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>true</IsTruncated><Contents><Key>dir/a</Key></Contents></ListBucketResult>`)
+		case r.Method == http.MethodGet:
+			fmt.Fprint(w, `<ListBucketResult><IsTruncated>false</IsTruncated><Contents><Key>dir/b</Key></Contents></ListBucketResult>`)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Signature, cfg.Access, cfg.Secret, cfg.Bucket = V5, "cos-access", secret, "test-1250000000"
+	o := mustNew(COS, cfg)
+	err := o.PutObjectData(ctx, "dir/附件 a+b%#?.txt", []byte("data"), &PutOptions{Metadata: map[string]string{"Owner": "test"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := o.ListObjects(ctx, "dir/", "", "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.NextMarker != "dir/a" || result.NextContinuationToken != "" {
+		t.Fatalf("next marker: %q, next token: %q", result.NextMarker, result.NextContinuationToken)
+	}
+	result, err = o.ListObjects(ctx, "dir/", "", result.NextMarker, 1)
+	if err != nil || result.IsTruncated || result.Objects[0].Key != "dir/b" {
+		t.Fatal(result, err)
+	}
+}
+
+func TestPayloadSigning(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Header.Get("x-amz-content-sha256") != fmt.Sprintf("%x", Sha256(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("ETag", `"etag"`)
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.PayloadSigning = true
+	o := mustNew(AWS, cfg)
+	if err := o.PutObjectData(ctx, "signed", []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.UploadPart(ctx, "signed", "upload-id", 1, []byte("part")); err != nil {
+		t.Fatal(err)
+	}
+	// 未开启时为UNSIGNED-PAYLOAD
+	var e *Error
+	if err := newTestOSSI(srv).PutObjectData(ctx, "unsigned", []byte("payload")); !errors.As(err, &e) || e.StatusCode != http.StatusBadRequest {
+		t.Fatal(err)
+	}
+}
+
+func TestPutObjectStreaming(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 15000) // 3个分块
+	var attempts int32
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if atomic.AddInt32(&attempts, 1) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		if r.Header.Get("x-amz-content-sha256") != "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" ||
+			r.Header.Get("x-amz-decoded-content-length") != strconv.Itoa(len(data)) ||
+			r.ContentLength != int64(len(body)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		// 按分块解码并校验签名链
+		_, seed, _ := strings.Cut(r.Header.Get("authorization"), "Signature=")
+		iso := r.Header.Get("x-amz-date")
+		scope := iso[:8] + "/us-east-1/s3/aws4_request"
+		key := HmacSha256(HmacSha256(HmacSha256(HmacSha256([]byte("AWS4***"), []byte(iso[:8])), []byte("us-east-1")), []byte("s3")), []byte("aws4_request"))
+		var decoded []byte
+		previous := seed
+		for {
+			line, rest, _ := bytes.Cut(body, []byte("\r\n"))
+			size, signature, _ := strings.Cut(string(line), ";chunk-signature=")
+			n, _ := strconv.ParseInt(size, 16, 64)
+			chunk := rest[:n]
+			stringToSign := "AWS4-HMAC-SHA256-PAYLOAD\n" + iso + "\n" + scope + "\n" + previous + "\n" +
+				fmt.Sprintf("%x", Sha256(nil)) + "\n" + fmt.Sprintf("%x", Sha256(chunk))
+			if signature != fmt.Sprintf("%x", HmacSha256(key, []byte(stringToSign))) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			previous = signature
+			decoded = append(decoded, chunk...)
+			body = rest[n+2:]
+			if n == 0 {
+				break
+			}
+		}
+		if !bytes.Equal(decoded, data) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.PayloadSigning = true
+	o := mustNew(AWS, cfg)
+	if err := o.PutObject(ctx, "stream", int64(len(data)), bytes.NewReader(data)); err != nil {
+		t.Fatal(err)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts: %d", attempts)
+	}
+}
+
+func TestPutObjectStreamingLength(t *testing.T) {
+	// 内容超过声明的长度时只编码声明的部分, 与Content-Length一致
+	signer := newChunkSigner("AWS4-HMAC-SHA256-PAYLOAD", "20060102T150405Z", "20060102/us-east-1/s3/aws4_request", []byte("key"), "seed")
+	encoded, err := io.ReadAll(newChunkReader(bytes.NewReader(bs), 10, signer))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(len(encoded)) != signer.EncodedLength(10) || !bytes.Contains(encoded, []byte("\r\n"+string(bs[:10])+"\r\n")) {
+		t.Fatalf("%q", encoded)
+	}
+}
+
+func TestSecurityToken(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-amz-security-token") != "sts-token" {
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer srv.Close()
+
+	for _, signature := range []string{V2, V4} {
+		cfg := newTestConfig(srv)
+		cfg.Signature, cfg.Token = signature, "sts-token"
+		o := mustNew(AWS, cfg)
+		if _, err := o.HeadObject(ctx, "token"); err != nil {
+			t.Fatal(signature, err)
+		}
+		link, err := o.GetObjectLink(ctx, "token", 60)
+		if err != nil {
+			t.Fatal(signature, err)
+		}
+		if !strings.Contains(strings.ToLower(link), "x-amz-security-token=sts-token") {
+			t.Fatal(signature, link)
+		}
+	}
+
+	// 阿里云V1外链: security-token作为子资源(未编码)加入CanonicalizedResource, 不签名header
+	const token = "sts/token+1="
+	config := &StorageConfig{Access: "***", Secret: "***", Token: token, Bucket: "test", Domain: "test.oss-cn-hangzhou.aliyuncs.com"}
+	u, err := url.Parse(mustStorage(NewStorageV2("", config, ProfileOSS)).PresignURL(http.MethodGet, "token", 60, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	stringToSign := "GET\n\n\n" + q.Get("Expires") + "\n/test/token?security-token=" + token
+	if q.Get("Signature") != base64.StdEncoding.EncodeToString(HmacSha1([]byte("***"), []byte(stringToSign))) {
+		t.Fatal("signature mismatch", u)
+	}
+	if len(q["security-token"]) != 1 || q.Get("security-token") != token {
+		t.Fatal(u)
+	}
+
+	// 未声明SignedRawQuery时子资源按RFC3986编码签名, 与SignedRawKey无关
+	profile := *ProfileOSS
+	profile.SignedRawQuery = false
+	if u, err = url.Parse(mustStorage(NewStorageV2("", config, &profile)).PresignURL(http.MethodGet, "token", 60, nil)); err != nil {
+		t.Fatal(err)
+	}
+	q = u.Query()
+	stringToSign = "GET\n\n\n" + q.Get("Expires") + "\n/test/token?security-token=" + url.PathEscape(token)
+	if q.Get("Signature") != base64.StdEncoding.EncodeToString(HmacSha1([]byte("***"), []byte(stringToSign))) {
+		t.Fatal("signature mismatch", u)
+	}
+}
+
+func TestObjectKeyEncoding(t *testing.T) {
+	const key = "dir/附件 a+b%#?.txt"
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/dir/%E9%99%84%E4%BB%B6%20a%2Bb%25%23%3F.txt" || r.URL.Path != "/"+key {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	o := newTestOSSI(srv)
+	if _, err := o.HeadObject(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if link, _ := o.GetObjectLink(ctx, key, 60); !strings.Contains(link, "/dir/%E9%99%84%E4%BB%B6%20a%2Bb%25%23%3F.txt?") {
+		t.Fatal(link)
+	}
+}
+
+func TestPresignURL(t *testing.T) {
+	// V2: 按StringToSign独立重算签名, 客户端必须带绑定的Content-Type/Content-MD5
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		stringToSign := r.Method + "\n" + r.Header.Get("Content-MD5") + "\n" + r.Header.Get("Content-Type") + "\n" +
+			q.Get("Expires") + "\n/test" + r.URL.EscapedPath() + "?partNumber=" + q.Get("partNumber") + "&uploadId=" + q.Get("uploadId")
+		if q.Get("Signature") != base64.StdEncoding.EncodeToString(HmacSha1([]byte("***"), []byte(stringToSign))) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		io.Copy(io.Discard, r.Body)
+	}))
+	defer srv.Close()
+
+	cfg := newTestConfig(srv)
+	cfg.Signature = V2
+	o := mustNew(AWS, cfg)
+	data := []byte("part")
+	md5 := base64.StdEncoding.EncodeToString(Md5(data))
+	link, err := o.PresignURL(ctx, http.MethodPut, "附件.txt", 60, &PresignOptions{
+		ContentType: "text/plain",
+		ContentMD5:  md5,
+		UploadId:    "upload-id",
+		PartNumber:  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := srv.Client()
+	for _, contentType := range []string{"text/plain", "text/html"} {
+		req, _ := http.NewRequest(http.MethodPut, link, bytes.NewReader(data))
+		req.Header.Set("Content-Type", contentType)
+		req.Header.Set("Content-MD5", md5)
+		rsp, err := client.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsp.Body.Close()
+		if (rsp.StatusCode == http.StatusOK) != (contentType == "text/plain") {
+			t.Fatal(contentType, rsp.Status)
+		}
+	}
+
+	// V4: 绑定的header加入SignedHeaders
+	link, err = newTestOSSI(srv).PresignURL(ctx, http.MethodPut, "key", 60, &PresignOptions{ContentType: "text/plain"})
+	if err != nil || !strings.Contains(link, "X-Amz-SignedHeaders=content-type%3Bhost") {
+		t.Fatal(link, err)
+	}
+	if _, err = o.PresignURL(ctx, http.MethodPost, "key", 60); err == nil {
+		t.Fatal("expect unsupported method")
+	}
+	if _, err = o.PresignURL(ctx, http.MethodGet, "key", 60, &PresignOptions{UploadId: "upload-id", PartNumber: 1}); err == nil {
+		t.Fatal("expect upload part requires PUT")
+	}
+	if _, err = o.PresignURL(ctx, http.MethodPut, "key", 60, &PresignOptions{UploadId: "upload-id"}); err == nil {
+		t.Fatal("expect invalid part number")
+	}
+}
+
+func TestPostPolicy(t *testing.T) {
+	policy := &PostPolicy{
+		KeyPrefix:           "upload/",
+		ContentType:         "image/png",
+		ContentLengthMax:    1024,
+		SuccessActionStatus: 201,
+	}
+	o := mustNew(AWS, &Config{
+		Signature: V4,
+		Prefix:    "app/",
+		StorageConfig: StorageConfig{
+			Access: "***",
+			Secret: "***",
+			Token:  "sts-token",
+			Region: "us-east-1",
+			Bucket: "test",
+			Domain: "test.s3.amazonaws.com",
+		},
+	})
+	if _, err := o.PostPolicy(ctx, nil); err == nil {
+		t.Fatal("nil policy accepted")
+	}
+	form, err := o.PostPolicy(ctx, policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f := form.Fields
+	if form.Url != "https://test.s3.amazonaws.com/" || f["key"] != "app/upload/${filename}" || f["x-amz-security-token"] != "sts-token" {
+		t.Fatal(form)
+	}
+	date := f["x-amz-date"][:8]
+	key := HmacSha256(HmacSha256(HmacSha256(HmacSha256([]byte("AWS4***"), []byte(date)), []byte("us-east-1")), []byte("s3")), []byte("aws4_request"))
+	if f["x-amz-signature"] != fmt.Sprintf("%x", HmacSha256(key, []byte(f["policy"]))) {
+		t.Fatal("signature mismatch")
+	}
+	raw, _ := base64.StdEncoding.DecodeString(f["policy"])
+	for _, cond := range []string{`["starts-with","$key","app/upload/"]`, `["content-length-range",0,1024]`, `{"success_action_status":"201"}`, `{"x-amz-credential":"***/` + date} {
+		if !strings.Contains(string(raw), cond) {
+			t.Fatal(cond, string(raw))
+		}
+	}
+}
+
+func TestObjectVersions(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodGet && q.Has("versions"):
+			if q.Get("key-marker") == "" {
+				w.Write([]byte(`<ListVersionsResult><IsTruncated>true</IsTruncated><NextKeyMarker>a</NextKeyMarker><NextVersionIdMarker>v1</NextVersionIdMarker>` +
+					`<DeleteMarker><Key>a</Key><VersionId>v2</VersionId><IsLatest>true</IsLatest></DeleteMarker>` +
+					`<Version><Key>a</Key><VersionId>v1</VersionId><IsLatest>false</IsLatest><Size>3</Size></Version></ListVersionsResult>`))
+				return
+			}
+			if q.Get("version-id-marker") != "v1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.Write([]byte(`<ListVersionsResult><IsTruncated>false</IsTruncated>` +
+				`<Version><Key>b</Key><VersionId>v1</VersionId><IsLatest>true</IsLatest><Size>5</Size></Version></ListVersionsResult>`))
+		case r.Method == http.MethodGet && q.Get("versionId") == "v1":
+			w.Write([]byte("old"))
+		case r.Method == http.MethodPut && r.Header.Get("x-amz-copy-source") == "/test/a?versionId=v1":
+			w.Header().Set("x-amz-version-id", "v3")
+			w.Header().Set("x-amz-copy-source-version-id", "v1")
+			w.Write([]byte(`<CopyObjectResult><ETag>"etag"</ETag></CopyObjectResult>`))
+		case r.Method == http.MethodDelete && q.Get("versionId") == "v2":
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	o := newTestOSSI(srv)
+	var versions []string
+	it := NewObjectVersionIterator(o, "", 2)
+	for it.Next(ctx) {
+		v := it.Version()
+		versions = append(versions, fmt.Sprintf("%s@%s:%v", v.Key, v.VersionId, v.DeleteMarker))
+	}
+	if it.Err() != nil || strings.Join(versions, ",") != "a@v2:true,a@v1:false,b@v1:false" {
+		t.Fatal(versions, it.Err())
+	}
+
+	_, rc, err := o.GetObject(ctx, "a", nil, &ObjectOptions{VersionId: "v1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "old" {
+		t.Fatal(string(data))
+	}
+	result, err := o.CopyObject(ctx, "a", "a", &CopyOptions{SourceVersionId: "v1"})
+	if err != nil || result.VersionId != "v3" || result.SourceVersionId != "v1" {
+		t.Fatal(result, err)
+	}
+	// 删除删除标记即恢复对象
+	if err = o.DeleteObject(ctx, "a", &ObjectOptions{VersionId: "v2"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestObjectTagging(t *testing.T) {
+	var tagging []byte
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case !q.Has("tagging"):
+			// PutObject及InitiateMultipartUpload通过header设置标签
+			if r.Header.Get("x-amz-tagging") != "class=spam&note=legal+hold" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if q.Has("uploads") {
+				w.Write([]byte(`<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>`))
+			}
+		case r.Method == http.MethodPut:
+			tagging, _ = io.ReadAll(r.Body)
+			if r.Header.Get("Content-MD5") != base64.StdEncoding.EncodeToString(Md5(tagging)) || q.Get("versionId") != "v1" {
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		case r.Method == http.MethodGet:
+			w.Write(tagging)
+		case r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer srv.Close()
+
+	o := newTestOSSI(srv)
+	tags := []*Tag{{Key: "note", Value: "legal hold"}, {Key: "class", Value: "spam"}}
+	if err := o.PutObjectData(ctx, "mail", []byte("data"), &PutOptions{Tags: tags}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.InitiateMultipartUpload(ctx, "mail", &PutOptions{Tags: tags}); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.PutObjectTagging(ctx, "mail", tags, &ObjectOptions{VersionId: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	result, err := o.GetObjectTagging(ctx, "mail")
+	if err != nil || len(result) != 2 || *result[0] != *tags[0] {
+		t.Fatal(result, err)
+	}
+	if err = o.DeleteObjectTagging(ctx, "mail"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 各云厂使用各自的标签header
+	config := &StorageConfig{Access: "***", Secret: "***", Region: "us-east-1", Bucket: "test", Domain: "test.example.com"}
+	for use, p := range profiles {
+		for _, signature := range []Signature{NewStorageV2, NewStorageV4, NewStorageV5} {
+			storage, err := signature("", config, p)
+			if err != nil {
+				// 云厂不支持的签名版本
+				continue
+			}
+			set := storage.PutObject("mail", "", &PutOptions{Tags: tags})
+			if set.Header[p.TaggingHeader] != "class=spam&note=legal+hold" {
+				t.Fatal(use, set.Header)
+			}
+			// 默认COPY元数据指令时同样替换标签
+			set = storage.CopyObject("mail", "mail-copy", &CopyOptions{PutOptions: PutOptions{Tags: tags}})
+			if set.Header[p.TaggingHeader] != "class=spam&note=legal+hold" || set.Header[p.TaggingHeader+"-directive"] != "REPLACE" {
+				t.Fatal(use, set.Header)
+			}
+		}
+	}
+}
+
+func TestNewUnsupportedSignature(t *testing.T) {
+	cfg := &Config{StorageConfig: StorageConfig{Access: "***", Secret: "***", Bucket: "test", Domain: "test.example.com"}}
+	for use, signatures := range map[string][]string{COS: {V2, V4}, KS3: {V5, "v3"}, AWS: {V5}, "gcs": {V4}} {
+		for _, signature := range signatures {
+			cfg.Signature = signature
+			if _, err := New(use, cfg); err == nil {
+				t.Fatal(use, signature)
+			}
+		}
+	}
+	if _, err := NewStorageV5("", &cfg.StorageConfig, ProfileKS3); err == nil {
+		t.Fatal("ks3 v5")
+	}
+	if _, err := NewBucketAdmin(COS, cfg); err == nil {
+		t.Fatal("cos v4")
+	}
+	cfg.Signature = V5
+	if _, err := New(COS, cfg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestOSSI 连接到httptest.NewTLSServer的OSSI(亚马逊V4签名)
+func newTestOSSI(srv *httptest.Server) OSSI {
+	return mustNew(AWS, newTestConfig(srv))
+}
+
+// mustNew 创建OSSI, 云厂不支持签名版本时panic. 用于配置固定的测试
+func mustNew(use string, config *Config) OSSI {
+	o, err := New(use, config)
+	if err != nil {
+		panic(err)
+	}
+	return o
+}
+
+// mustStorage 同mustNew, 用于直接测试Storage
+func mustStorage(s Storage, err error) Storage {
+	if err != nil {
+		panic(err)
+	}
+	return s
+}
+
+// newTestConfig 连接到httptest.NewTLSServer的配置(V4签名), 测试按需修改profile相关的设置
+func newTestConfig(srv *httptest.Server) *Config {
+	return &Config{
+		Signature: V4,
+		StorageConfig: StorageConfig{
+			Access: "***",
+			Secret: "***",
+			Region: "us-east-1",
+			Bucket: "test",
+			Domain: strings.TrimPrefix(srv.URL, "https://"),
+		},
+		ClientConfig: ClientConfig{
+			InsecureSkipVerify: true,
+		},
+		RetryConfig: RetryConfig{
+			BaseDelay: time.Millisecond,
+		},
+	}
+}
+
+// verifySignatureV4 按请求独立重算V4签名(secret为***, region为us-east-1)并与Authorization比较.
+// 未声明SignedHeaders的profile(阿里云)按规范签名content-type, content-md5及x-oss-*头
+func verifySignatureV4(r *http.Request, p *Profile, bucket string) bool {
+	auth := r.Header.Get("Authorization")
+	_, signature, _ := strings.Cut(auth, "Signature=")
+
+	var names []string
+	if _, rest, ok := strings.Cut(auth, "SignedHeaders="); ok {
+		list, _, _ := strings.Cut(rest, ",")
+		names = strings.Split(list, ";")
+	} else {
+		for k := range r.Header {
+			if k = strings.ToLower(k); k == "content-type" || k == "content-md5" || strings.HasPrefix(k, "x-oss-") {
+				names = append(names, k)
+			}
+		}
+		sort.Strings(names)
+	}
+	var headers strings.Builder
+	for _, k := range names {
+		v := r.Header.Get(k)
+		if k == "host" {
+			v = r.Host
+		}
+		headers.WriteString(k + ":" + strings.TrimSpace(v) + "\n")
+	}
+
+	query := r.URL.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for i, k := range keys {
+		keys[i] = UriEncode(k, true) + "=" + UriEncode(query.Get(k), true)
+	}
+
+	uri := r.URL.EscapedPath()
+	if p.SignedBucketURI {
+		uri = "/" + bucket + uri
+	}
+	signedHeaders := ""
+	if p.SignedHostHeader {
+		signedHeaders = strings.Join(names, ";")
+	}
+	canonical := r.Method + "\n" + uri + "\n" + strings.Join(keys, "&") + "\n" + headers.String() + "\n" + signedHeaders + "\n" + r.Header.Get(p.ContentSHA256Header)
+
+	iso := r.Header.Get(p.DateHeader)
+	scope := iso[:8] + "/us-east-1/" + p.V4Service + "/" + p.V4Boundary
+	stringToSign := p.V4Algorithm + "\n" + iso + "\n" + scope + "\n" + fmt.Sprintf("%x", Sha256([]byte(canonical)))
+	key := HmacSha256(HmacSha256(HmacSha256(HmacSha256([]byte(p.V4Code+"***"), []byte(iso[:8])), []byte("us-east-1")), []byte(p.V4Service)), []byte(p.V4Boundary))
+	return signature == fmt.Sprintf("%x", HmacSha256(key, []byte(stringToSign)))
+}
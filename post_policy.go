@@ -0,0 +1,91 @@
+package oss
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultPostPolicyExpires = time.Hour                  // 表单上传策略默认有效期
+	postPolicyExpiration     = "2006-01-02T15:04:05.000Z" // 策略过期时间格式(ISO8601 UTC)
+	postPolicyFilename       = "${filename}"              // 表单上传时由服务端替换为文件名
+)
+
+// PostPolicy 浏览器表单上传(POST Object)的策略条件
+type PostPolicy struct {
+	Key                 string        `json:"key"`                   // 对象key(自动拼接key前缀), 与KeyPrefix二选一
+	KeyPrefix           string        `json:"key_prefix"`            // key前缀条件(starts-with, 自动拼接key前缀), 表单key默认为KeyPrefix+${filename}
+	ContentType         string        `json:"content_type"`          // 限定Content-Type
+	ContentLengthMin    int64         `json:"content_length_min"`    // content-length-range下限
+	ContentLengthMax    int64         `json:"content_length_max"`    // content-length-range上限, 0表示不限制
+	Expires             time.Duration `json:"expires"`               // 策略有效期(默认1小时)
+	SuccessActionStatus int           `json:"success_action_status"` // 上传成功返回的状态(200,201,204), 0表示默认
+}
+
+// PostForm 表单上传的url及需要提交的字段(file字段须放在最后)
+type PostForm struct {
+	Url    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
+// postPolicyBuilder 收集策略条件及表单字段, 条件与字段保持一致
+type postPolicyBuilder struct {
+	fields     map[string]string
+	conditions []interface{}
+}
+
+/*
+newPostPolicyBuilder 添加通用条件: bucket, key, content-length-range, Content-Type, success_action_status
+签名相关的条件及字段由各签名版本添加
+*/
+func newPostPolicyBuilder(prefix string, bucket string, p *PostPolicy) *postPolicyBuilder {
+	b := &postPolicyBuilder{fields: make(map[string]string)}
+	b.conditions = append(b.conditions, map[string]string{"bucket": bucket})
+	if p.Key != "" {
+		b.add("key", prefix+p.Key)
+	} else {
+		b.fields["key"] = prefix + p.KeyPrefix + postPolicyFilename
+		b.conditions = append(b.conditions, []string{"starts-with", "$key", prefix + p.KeyPrefix})
+	}
+	if p.ContentLengthMin > 0 || p.ContentLengthMax > 0 {
+		max := p.ContentLengthMax
+		if max == 0 {
+			max = 5 * 1024 * 1024 * 1024 // 单次上传最大5G
+		}
+		b.conditions = append(b.conditions, []interface{}{"content-length-range", p.ContentLengthMin, max})
+	}
+	if p.ContentType != "" {
+		b.add("Content-Type", p.ContentType)
+	}
+	if p.SuccessActionStatus != 0 {
+		b.add("success_action_status", strconv.Itoa(p.SuccessActionStatus))
+	}
+	return b
+}
+
+// add 添加精确匹配的条件及对应的表单字段
+func (b *postPolicyBuilder) add(name string, value string) {
+	b.fields[name] = value
+	b.conditions = append(b.conditions, map[string]string{name: value})
+}
+
+// encode 生成策略JSON及其base64, 并将base64加入policy字段
+func (b *postPolicyBuilder) encode(expiration time.Time) (policy []byte, policy64 string) {
+	policy, _ = json.Marshal(map[string]interface{}{
+		"expiration": expiration.UTC().Format(postPolicyExpiration),
+		"conditions": b.conditions,
+	})
+	policy64 = base64.StdEncoding.EncodeToString(policy)
+	b.fields["policy"] = policy64
+	return
+}
+
+// postUrl 表单上传的url(不含key)
+func postUrl(p *Profile, c *StorageConfig) string {
+	if p.AccessBucketURI {
+		return p.Schema + "://" + c.Domain + "/" + c.Bucket + "/"
+	}
+	return p.Schema + "://" + c.Domain + "/"
+}
@@ -1,5 +1,7 @@
 package oss
 
+import "fmt"
+
 const (
 	schemaHttp  = "http"
 	schemaHttps = "https"
@@ -12,22 +14,62 @@ type Profile struct {
 	V4Service           string            // 在V4用作服务名称
 	V4Algorithm         string            // 在V4用作算法名称
 	V4Boundary          string            // 在V4用作边界标志
+	V5Algorithm         string            // 在V5用作算法名称(q-sign-algorithm)
 	Schema              string            // endpoint 地址 schema, http 或者 https
 	AccessBucketURI     bool              // 访问URI携带bucket
 	SignedBucketURI     bool              // 签名URI携带bucket
 	SignedHostHeader    bool              // 在V4是否将Host加入StringToSign
+	SignedRawKey        bool              // 在V2和V5签名使用未编码的key(url中总是按RFC3986编码)
+	SignedRawQuery      bool              // 在V2签名使用未编码的子资源(query)值(url中总是按RFC3986编码)
 	SignedDateHeader    bool              // 在V2是否将Date加入StringToSign
 	DateHeader          string            // 在V2和V4用于代替Date的header名称(小写)
 	ContentSHA256Header string            // 在V2和V4用于Content-Sha256的header名称(小写)
+	RequestIdHeader     string            // 响应中请求ID的header名称(小写)
+	MetaHeaderPrefix    string            // 用户元数据header前缀(小写)
+	StorageClassHeader  string            // 存储类型的header名称(小写)
+	EncryptionHeader    string            // 服务端加密的header名称(小写)
+	VersionIdHeader     string            // 版本ID的header名称(小写)
+	CopySourceHeader    string            // 复制源的header名称(小写)
+	CopyRangeHeader     string            // 复制源范围的header名称(小写)
+	DirectiveHeader     string            // 复制元数据指令的header名称(小写)
+	SecurityTokenHeader string            // 临时凭证安全令牌的header名称(小写), V5外链也用作query名称
+	V2TokenSubResource  bool              // 在V2外链将安全令牌作为子资源(query)加入签名, 否则按header加入签名
+	AclHeader           string            // 访问权限(ACL)的header名称(小写)
+	TaggingHeader       string            // 上传时设置对象标签的header名称(小写)
+	StorageClassIA      string            // 低频存储类型名称, 用于生命周期转换. 为空表示不转换名称
+	StorageClassArchive string            // 归档存储类型名称, 用于生命周期转换. 为空表示不转换名称
+	StreamingPayload    string            // 在V4流式签名的Content-Sha256值, 为空表示不支持流式签名
+	StreamingAlgorithm  string            // 在V4流式签名的分块签名算法名称
+	DecodedLengthHeader string            // 在V4流式签名的原始内容长度header名称(小写)
 	StorageHeaders      map[string]string // 在V2和V4上传对象存储设置,用于PutObject或MultipartUpload等上传header设置
 	V2QueryParams       V2QueryParams     // 在V2用作Query参数名称
 	V4QueryParams       V4QueryParams     // 在V4用作Query参数名称
+	V2PostFields        V2PostFields      // 在V2用作表单上传的字段名称
+	V4PostFields        V4PostFields      // 在V4用作表单上传的字段名称
+}
+
+// checkSignature 校验profile是否具备签名版本所需的设置, 例如COS仅支持V5, 其它云厂不支持V5
+func (p *Profile) checkSignature(signature string) error {
+	var ok bool
+	switch signature {
+	case V2:
+		ok = p.V2Code != ""
+	case V4:
+		ok = p.V4Code != "" && p.V4Service != "" && p.V4Algorithm != "" && p.V4Boundary != ""
+	case V5:
+		ok = p.V5Algorithm != ""
+	}
+	if !ok {
+		return fmt.Errorf("signature %q not supported", signature)
+	}
+	return nil
 }
 
 type V2QueryParams struct {
-	AccessKeyId string // AccessKeyId的参数名称
-	Expires     string // Expires的参数名称
-	Signature   string // Signature的参数名称
+	AccessKeyId   string // AccessKeyId的参数名称
+	Expires       string // Expires的参数名称
+	Signature     string // Signature的参数名称
+	SecurityToken string // 安全令牌的参数名称
 }
 
 type V4QueryParams struct {
@@ -37,6 +79,21 @@ type V4QueryParams struct {
 	Expires       string
 	SignedHeaders string
 	Signature     string
+	SecurityToken string
+}
+
+type V2PostFields struct {
+	AccessKeyId   string // AccessKeyId的字段名称
+	Signature     string // Signature的字段名称
+	SecurityToken string // 安全令牌的字段名称
+}
+
+type V4PostFields struct {
+	Algorithm     string
+	Credential    string
+	Date          string
+	Signature     string
+	SecurityToken string
 }
 
 // ProfileKS3 KS3配置
@@ -53,15 +110,29 @@ var ProfileKS3 = &Profile{
 	SignedDateHeader:    true, // KS3需要将Date加到StringToSign
 	DateHeader:          "x-kss-date",
 	ContentSHA256Header: "x-kss-content-sha256",
+	RequestIdHeader:     "x-kss-request-id",
+	MetaHeaderPrefix:    "x-kss-meta-",
+	StorageClassHeader:  "x-kss-storage-class",
+	EncryptionHeader:    "x-kss-server-side-encryption",
+	VersionIdHeader:     "x-kss-version-id",
+	CopySourceHeader:    "x-kss-copy-source",
+	CopyRangeHeader:     "x-kss-copy-source-range",
+	DirectiveHeader:     "x-kss-metadata-directive",
+	SecurityTokenHeader: "x-kss-security-token",
+	AclHeader:           "x-kss-acl",
+	TaggingHeader:       "x-kss-tagging",
+	StorageClassIA:      "STANDARD_IA",
+	StorageClassArchive: "ARCHIVE",
 	StorageHeaders: map[string]string{
 		"x-kss-server-side-encryption": "AES256",
 		"x-kss-acl":                    "private",
 		"x-kss-auto-compress":          "true",
 	},
 	V2QueryParams: V2QueryParams{
-		AccessKeyId: "KSSAccessKeyId",
-		Expires:     "Expires",
-		Signature:   "Signature",
+		AccessKeyId:   "KSSAccessKeyId",
+		Expires:       "Expires",
+		Signature:     "Signature",
+		SecurityToken: "x-kss-security-token",
 	},
 	V4QueryParams: V4QueryParams{
 		Algorithm:     "X-Kss-Algorithm",
@@ -70,6 +141,19 @@ var ProfileKS3 = &Profile{
 		Expires:       "X-Kss-Expires",
 		SignedHeaders: "X-Kss-SignedHeaders",
 		Signature:     "X-Kss-Signature",
+		SecurityToken: "X-Kss-Security-Token",
+	},
+	V2PostFields: V2PostFields{
+		AccessKeyId:   "KSSAccessKeyId",
+		Signature:     "signature",
+		SecurityToken: "x-kss-security-token",
+	},
+	V4PostFields: V4PostFields{
+		Algorithm:     "x-kss-algorithm",
+		Credential:    "x-kss-credential",
+		Date:          "x-kss-date",
+		Signature:     "x-kss-signature",
+		SecurityToken: "x-kss-security-token",
 	},
 }
 
@@ -87,14 +171,28 @@ var ProfileOBS = &Profile{
 	SignedDateHeader:    false, // 当存在x-obs-date时,Date参数按照空字符串处理!
 	DateHeader:          "x-obs-date",
 	ContentSHA256Header: "x-obs-content-sha256",
+	RequestIdHeader:     "x-obs-request-id",
+	MetaHeaderPrefix:    "x-obs-meta-",
+	StorageClassHeader:  "x-obs-storage-class",
+	EncryptionHeader:    "x-obs-server-side-encryption",
+	VersionIdHeader:     "x-obs-version-id",
+	CopySourceHeader:    "x-obs-copy-source",
+	CopyRangeHeader:     "x-obs-copy-source-range",
+	DirectiveHeader:     "x-obs-metadata-directive",
+	SecurityTokenHeader: "x-obs-security-token",
+	AclHeader:           "x-obs-acl",
+	TaggingHeader:       "x-obs-tagging",
+	StorageClassIA:      "WARM",
+	StorageClassArchive: "COLD",
 	StorageHeaders: map[string]string{
 		"x-obs-server-side-encryption": "AES256",
 		"x-obs-acl":                    "private",
 	},
 	V2QueryParams: V2QueryParams{
-		AccessKeyId: "AccessKeyId",
-		Expires:     "Expires",
-		Signature:   "Signature",
+		AccessKeyId:   "AccessKeyId",
+		Expires:       "Expires",
+		Signature:     "Signature",
+		SecurityToken: "x-obs-security-token",
 	},
 	V4QueryParams: V4QueryParams{
 		Algorithm:     "X-Obs-Algorithm",
@@ -103,6 +201,19 @@ var ProfileOBS = &Profile{
 		Expires:       "X-Obs-Expires",
 		SignedHeaders: "X-Obs-SignedHeaders",
 		Signature:     "X-Obs-Signature",
+		SecurityToken: "X-Obs-Security-Token",
+	},
+	V2PostFields: V2PostFields{
+		AccessKeyId:   "AccessKeyId",
+		Signature:     "signature",
+		SecurityToken: "x-obs-security-token",
+	},
+	V4PostFields: V4PostFields{
+		Algorithm:     "x-obs-algorithm",
+		Credential:    "x-obs-credential",
+		Date:          "x-obs-date",
+		Signature:     "x-obs-signature",
+		SecurityToken: "x-obs-security-token",
 	},
 }
 
@@ -120,14 +231,31 @@ var ProfileAWS = &Profile{
 	SignedDateHeader:    true, // 需要将Date加到StringToSign
 	DateHeader:          "x-amz-date",
 	ContentSHA256Header: "x-amz-content-sha256",
+	RequestIdHeader:     "x-amz-request-id",
+	MetaHeaderPrefix:    "x-amz-meta-",
+	StorageClassHeader:  "x-amz-storage-class",
+	EncryptionHeader:    "x-amz-server-side-encryption",
+	VersionIdHeader:     "x-amz-version-id",
+	CopySourceHeader:    "x-amz-copy-source",
+	CopyRangeHeader:     "x-amz-copy-source-range",
+	DirectiveHeader:     "x-amz-metadata-directive",
+	SecurityTokenHeader: "x-amz-security-token",
+	AclHeader:           "x-amz-acl",
+	TaggingHeader:       "x-amz-tagging",
+	StorageClassIA:      "STANDARD_IA",
+	StorageClassArchive: "GLACIER",
+	StreamingPayload:    "STREAMING-AWS4-HMAC-SHA256-PAYLOAD",
+	StreamingAlgorithm:  "AWS4-HMAC-SHA256-PAYLOAD",
+	DecodedLengthHeader: "x-amz-decoded-content-length",
 	StorageHeaders: map[string]string{
 		"x-amz-server-side-encryption": "AES256",
 		"x-amz-acl":                    "private",
 	},
 	V2QueryParams: V2QueryParams{
-		AccessKeyId: "AWSAccessKeyId",
-		Expires:     "Expires",
-		Signature:   "Signature",
+		AccessKeyId:   "AWSAccessKeyId",
+		Expires:       "Expires",
+		Signature:     "Signature",
+		SecurityToken: "x-amz-security-token",
 	},
 	V4QueryParams: V4QueryParams{
 		Algorithm:     "X-Amz-Algorithm",
@@ -136,6 +264,19 @@ var ProfileAWS = &Profile{
 		Expires:       "X-Amz-Expires",
 		SignedHeaders: "X-Amz-SignedHeaders",
 		Signature:     "X-Amz-Signature",
+		SecurityToken: "X-Amz-Security-Token",
+	},
+	V2PostFields: V2PostFields{
+		AccessKeyId:   "AWSAccessKeyId",
+		Signature:     "signature",
+		SecurityToken: "x-amz-security-token",
+	},
+	V4PostFields: V4PostFields{
+		Algorithm:     "x-amz-algorithm",
+		Credential:    "x-amz-credential",
+		Date:          "x-amz-date",
+		Signature:     "x-amz-signature",
+		SecurityToken: "x-amz-security-token",
 	},
 }
 
@@ -153,14 +294,30 @@ var ProfileMINIO = &Profile{
 	SignedDateHeader:    true, // 需要将Date加到StringToSign
 	DateHeader:          "x-amz-date",
 	ContentSHA256Header: "x-amz-content-sha256",
+	RequestIdHeader:     "x-amz-request-id",
+	MetaHeaderPrefix:    "x-amz-meta-",
+	StorageClassHeader:  "x-amz-storage-class",
+	EncryptionHeader:    "x-amz-server-side-encryption",
+	VersionIdHeader:     "x-amz-version-id",
+	CopySourceHeader:    "x-amz-copy-source",
+	CopyRangeHeader:     "x-amz-copy-source-range",
+	DirectiveHeader:     "x-amz-metadata-directive",
+	SecurityTokenHeader: "x-amz-security-token",
+	AclHeader:           "x-amz-acl",
+	TaggingHeader:       "x-amz-tagging",
+	// MinIO生命周期转换的目标为自定义的远端tier名称, 存储类型名称不转换
+	StreamingPayload:    "STREAMING-AWS4-HMAC-SHA256-PAYLOAD",
+	StreamingAlgorithm:  "AWS4-HMAC-SHA256-PAYLOAD",
+	DecodedLengthHeader: "x-amz-decoded-content-length",
 	StorageHeaders: map[string]string{
 		//"x-amz-server-side-encryption": "AES256", // 无法支持加密
 		"x-amz-acl": "private",
 	},
 	V2QueryParams: V2QueryParams{
-		AccessKeyId: "AWSAccessKeyId",
-		Expires:     "Expires",
-		Signature:   "Signature",
+		AccessKeyId:   "AWSAccessKeyId",
+		Expires:       "Expires",
+		Signature:     "Signature",
+		SecurityToken: "x-amz-security-token",
 	},
 	V4QueryParams: V4QueryParams{
 		Algorithm:     "X-Amz-Algorithm",
@@ -169,6 +326,19 @@ var ProfileMINIO = &Profile{
 		Expires:       "X-Amz-Expires",
 		SignedHeaders: "X-Amz-SignedHeaders",
 		Signature:     "X-Amz-Signature",
+		SecurityToken: "X-Amz-Security-Token",
+	},
+	V2PostFields: V2PostFields{
+		AccessKeyId:   "AWSAccessKeyId",
+		Signature:     "signature",
+		SecurityToken: "x-amz-security-token",
+	},
+	V4PostFields: V4PostFields{
+		Algorithm:     "x-amz-algorithm",
+		Credential:    "x-amz-credential",
+		Date:          "x-amz-date",
+		Signature:     "x-amz-signature",
+		SecurityToken: "x-amz-security-token",
 	},
 }
 
@@ -182,18 +352,35 @@ var ProfileOSS = &Profile{
 	Schema:              schemaHttps,
 	AccessBucketURI:     false,
 	SignedBucketURI:     true,
+	SignedRawKey:        true, // 阿里云V1签名的CanonicalizedResource使用未编码的key
+	SignedRawQuery:      true, // 阿里云V1签名的CanonicalizedResource使用未编码的子资源
+	V2TokenSubResource:  true, // 阿里云V1外链的security-token是CanonicalizedResource的子资源
 	SignedHostHeader:    false,
 	DateHeader:          "x-oss-date",
 	SignedDateHeader:    false, // 当存在x-obs-date时,Date参数按照空字符串处理!
 	ContentSHA256Header: "x-oss-content-sha256",
+	RequestIdHeader:     "x-oss-request-id",
+	MetaHeaderPrefix:    "x-oss-meta-",
+	StorageClassHeader:  "x-oss-storage-class",
+	EncryptionHeader:    "x-oss-server-side-encryption",
+	VersionIdHeader:     "x-oss-version-id",
+	CopySourceHeader:    "x-oss-copy-source",
+	CopyRangeHeader:     "x-oss-copy-source-range",
+	DirectiveHeader:     "x-oss-metadata-directive",
+	SecurityTokenHeader: "x-oss-security-token",
+	AclHeader:           "x-oss-acl",
+	TaggingHeader:       "x-oss-tagging",
+	StorageClassIA:      "IA",
+	StorageClassArchive: "Archive",
 	StorageHeaders: map[string]string{
 		"x-oss-server-side-encryption": "AES256",
 		"x-oss-acl":                    "private",
 	},
 	V2QueryParams: V2QueryParams{
-		AccessKeyId: "AccessKeyId",
-		Expires:     "Expires",
-		Signature:   "Signature",
+		AccessKeyId:   "AccessKeyId",
+		Expires:       "Expires",
+		Signature:     "Signature",
+		SecurityToken: "security-token",
 	},
 	V4QueryParams: V4QueryParams{
 		Algorithm:     "X-Oss-Signature-Version",
@@ -202,5 +389,44 @@ var ProfileOSS = &Profile{
 		Expires:       "X-Oss-Expires",
 		SignedHeaders: "X-Oss-Signed-headers",
 		Signature:     "X-Oss-Signature",
+		SecurityToken: "x-oss-security-token",
+	},
+	V2PostFields: V2PostFields{
+		AccessKeyId:   "OSSAccessKeyId",
+		Signature:     "Signature",
+		SecurityToken: "x-oss-security-token",
+	},
+	V4PostFields: V4PostFields{
+		Algorithm:     "x-oss-signature-version",
+		Credential:    "x-oss-credential",
+		Date:          "x-oss-date",
+		Signature:     "x-oss-signature",
+		SecurityToken: "x-oss-security-token",
+	},
+}
+
+// ProfileCOS 腾讯云COS(仅支持V5签名)
+var ProfileCOS = &Profile{
+	V5Algorithm:         "sha1",
+	Schema:              schemaHttps,
+	AccessBucketURI:     false,
+	SignedBucketURI:     false,
+	SignedRawKey:        true, // HttpString的UriPathname使用未编码的key
+	RequestIdHeader:     "x-cos-request-id",
+	MetaHeaderPrefix:    "x-cos-meta-",
+	StorageClassHeader:  "x-cos-storage-class",
+	EncryptionHeader:    "x-cos-server-side-encryption",
+	VersionIdHeader:     "x-cos-version-id",
+	CopySourceHeader:    "x-cos-copy-source",
+	CopyRangeHeader:     "x-cos-copy-source-range",
+	DirectiveHeader:     "x-cos-metadata-directive",
+	SecurityTokenHeader: "x-cos-security-token",
+	AclHeader:           "x-cos-acl",
+	TaggingHeader:       "x-cos-tagging",
+	StorageClassIA:      "STANDARD_IA",
+	StorageClassArchive: "ARCHIVE",
+	StorageHeaders: map[string]string{
+		"x-cos-server-side-encryption": "AES256",
+		"x-cos-acl":                    "private",
 	},
 }
@@ -0,0 +1,114 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+const (
+	defaultRetryMaxAttempts = 3
+	defaultRetryBaseDelay   = 100 * time.Millisecond
+	defaultRetryMaxDelay    = 5 * time.Second
+)
+
+var (
+	defaultRetryableStatus = []int{
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
+	defaultRetryableErrors = []error{
+		io.EOF,
+		io.ErrUnexpectedEOF, // 参见client.go的DisableKeepAlives
+		syscall.ECONNRESET,
+		syscall.ECONNABORTED,
+		syscall.ECONNREFUSED,
+		syscall.EPIPE,
+	}
+)
+
+// retryer 指数退避(带抖动)的重试策略
+type retryer struct {
+	maxAttempts int
+	baseDelay   time.Duration
+	maxDelay    time.Duration
+	status      []int
+	errors      []error
+}
+
+func newRetryer(c *RetryConfig) *retryer {
+	r := &retryer{
+		maxAttempts: NvlI(c.MaxAttempts, defaultRetryMaxAttempts),
+		baseDelay:   NvlD(c.BaseDelay, defaultRetryBaseDelay),
+		maxDelay:    NvlD(c.MaxDelay, defaultRetryMaxDelay),
+		status:      c.RetryableStatus,
+		errors:      c.RetryableErrors,
+	}
+	if r.status == nil {
+		r.status = defaultRetryableStatus
+	}
+	if r.errors == nil {
+		r.errors = defaultRetryableErrors
+	}
+	return r
+}
+
+// retryable 判断错误是否可重试. 调用方取消或超时的context不再重试
+func (r *retryer) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		for _, v := range r.status {
+			if e.StatusCode == v {
+				return true
+			}
+		}
+		return retryableCode(e.Code)
+	}
+
+	var ne net.Error
+	if errors.As(err, &ne) && ne.Timeout() {
+		return true
+	}
+	for _, v := range r.errors {
+		if errors.Is(err, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// unsent 请求是否未发出(建立连接失败). 超时或连接中断时服务端可能已执行请求
+func unsent(err error) bool {
+	var oe *net.OpError
+	return errors.As(err, &oe) && oe.Op == "dial"
+}
+
+// backoff 第attempt次失败后等待: min(maxDelay, baseDelay*2^(attempt-1)), 其中一半为随机抖动
+func (r *retryer) backoff(ctx context.Context, attempt int) error {
+	delay := r.baseDelay << (attempt - 1)
+	if delay > r.maxDelay || delay <= 0 {
+		delay = r.maxDelay
+	}
+	delay = delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
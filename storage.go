@@ -15,19 +15,70 @@ type SignatureV4 interface {
 	Link(ctx *ProviderContext, expires, signature string) string                                               // 生成下载外链
 }
 
+// SignatureV5 签名接口(腾讯云COS的q-sign-algorithm签名)
+type SignatureV5 interface {
+	Url(ctx *ProviderContext) string                                                 // url,封装通过query发送签名信息
+	Header(ctx *ProviderContext, keyTime string, signature string) map[string]string // header,封闭通过header发送签名信息
+	Signature(ctx *ProviderContext, keyTime string) string                           // 签名算法实现(keyTime为签名有效期)
+	Link(ctx *ProviderContext, keyTime string, signature string) string              // 生成下载外链
+}
+
 // Storage 用于邮箱服务的OSS提供者接口(是标准OSS接口子集)
 type Storage interface {
-	// PutObject V2的hash是Content-MD5, V4的hash是Content-SHA256
-	HeadObject(key string) *RequestSetting
-	PutObject(key string, contentMD5 string) *RequestSetting
-	GetObject(key string, _range *Range) *RequestSetting
+	// PutObject V2/V5的hash是Content-MD5, V4的hash是Content-SHA256(hex), 为空时服务端不校验
+	// HeadObject/GetObject/DeleteObject opts可指定版本ID(versionId加入签名的子资源)
+	HeadObject(key string, opts *ObjectOptions) *RequestSetting
+	PutObject(key string, contentMD5 string, opts *PutOptions) *RequestSetting
+	GetObject(key string, _range *Range, opts *ObjectOptions) *RequestSetting
 	GetObjectLink(key string, timeout int64) string
-	DeleteObject(key string) *RequestSetting
-	InitiateMultipartUpload(key string) *RequestSetting
-	// UploadPart V2的hash是Content-MD5, V4的hash是Content-SHA256
+	// PresignURL 预签名url, 支持GET/PUT/HEAD/DELETE, timeout为有效秒数. opts可绑定上传的Content-Type/Content-MD5或分片
+	PresignURL(method string, key string, timeout int64, opts *PresignOptions) string
+	// PostPolicy 浏览器表单上传的策略及签名字段, key会自动拼接key前缀
+	PostPolicy(policy *PostPolicy) *PostForm
+	DeleteObject(key string, opts *ObjectOptions) *RequestSetting
+	InitiateMultipartUpload(key string, opts *PutOptions) *RequestSetting
+	// UploadPart V2/V5的hash是Content-MD5, V4的hash是Content-SHA256(hex), 为空时服务端不校验
 	UploadPart(key string, uploadId string, partNumber int, contentMD5 string) *RequestSetting
 	CompleteMultipartUpload(key string, uploadId string) *RequestSetting
 	AbortMultipartUpload(key string, uploadId string) *RequestSetting
+	// CopyObject 服务端复制对象, srcKey与dstKey都拼接key前缀
+	CopyObject(srcKey string, dstKey string, opts *CopyOptions) *RequestSetting
+	// UploadPartCopy 复制源对象(srcVersionId为空表示当前版本)的范围(_range为nil时复制全部)作为分片, 用于复制超过5G的对象
+	UploadPartCopy(srcKey string, srcVersionId string, dstKey string, uploadId string, partNumber int, _range *Range) *RequestSetting
+	// DeleteObjects 批量删除对象(POST ?delete), 请求内容必须带Content-MD5
+	DeleteObjects(contentMD5 string) *RequestSetting
+	// ListParts 列举已上传分片, partNumberMarker用于分页
+	ListParts(key string, uploadId string, partNumberMarker int, maxParts int) *RequestSetting
+	// ListMultipartUploads 列举未完成的分片上传, prefix与keyMarker会自动拼接key前缀
+	ListMultipartUploads(prefix string, keyMarker string, uploadIdMarker string, maxUploads int) *RequestSetting
+	// ListObjects 列举对象(ListObjectsV2), prefix会自动拼接key前缀
+	ListObjects(prefix string, delimiter string, continuationToken string, maxKeys int) *RequestSetting
+	// ListObjectVersions 列举对象版本(含删除标记), prefix与keyMarker会自动拼接key前缀
+	ListObjectVersions(prefix string, delimiter string, keyMarker string, versionIdMarker string, maxKeys int) *RequestSetting
+	// PutObjectTagging 设置对象标签(覆盖原有标签), 请求内容为Tagging. opts可指定版本ID
+	PutObjectTagging(key string, contentMD5 string, opts *ObjectOptions) *RequestSetting
+	GetObjectTagging(key string, opts *ObjectOptions) *RequestSetting
+	DeleteObjectTagging(key string, opts *ObjectOptions) *RequestSetting
+}
+
+// BucketStorage 桶级别的请求(bucket由参数指定, 不使用StorageConfig.Bucket, 不拼接key前缀)
+type BucketStorage interface {
+	// CreateBucket 创建桶, 请求内容为CreateBucketConfiguration(LocationConstraint), acl为空时使用默认权限
+	CreateBucket(bucket string, acl string) *RequestSetting
+	HeadBucket(bucket string) *RequestSetting
+	DeleteBucket(bucket string) *RequestSetting
+	GetBucketLocation(bucket string) *RequestSetting
+	// PutBucketLifecycle 设置生命周期规则, 请求内容为LifecycleConfiguration
+	PutBucketLifecycle(bucket string, contentMD5 string) *RequestSetting
+	GetBucketLifecycle(bucket string) *RequestSetting
+	DeleteBucketLifecycle(bucket string) *RequestSetting
+	// PutBucketCors 设置跨域规则, 请求内容为CORSConfiguration
+	PutBucketCors(bucket string, contentMD5 string) *RequestSetting
+	GetBucketCors(bucket string) *RequestSetting
+	DeleteBucketCors(bucket string) *RequestSetting
+	// PutBucketVersioning 设置多版本状态, 请求内容为VersioningConfiguration
+	PutBucketVersioning(bucket string, contentMD5 string) *RequestSetting
+	GetBucketVersioning(bucket string) *RequestSetting
 }
 
 // RequestSetting Http请求设置
@@ -36,4 +87,54 @@ type RequestSetting struct {
 	Method string            `json:"Method,omitempty"` // http Method
 	Url    string            `json:"url,omitempty"`    // http url
 	Header map[string]string `json:"header,omitempty"` // http header
+	Chunk  *ChunkSigner      `json:"-"`                // 流式签名(aws-chunked), 非nil时请求内容按分块签名编码
+}
+
+// credentialsBinder 绑定凭证的Storage, 签名使用绑定的凭证(不再访问StorageConfig的provider)
+type credentialsBinder interface {
+	withCredentials(cred *Credentials) Storage
+}
+
+// StreamingStorage 支持V4流式签名(aws-chunked)的Storage, 需要profile设置StreamingPayload
+type StreamingStorage interface {
+	// PutObjectStreaming 流式签名上传对象, decodedLength为原始内容长度
+	PutObjectStreaming(key string, decodedLength int64, opts *PutOptions) *RequestSetting
+}
+
+// PresignOptions 预签名url的可选设置
+type PresignOptions struct {
+	ContentType string `json:"content_type"` // 绑定Content-Type, 客户端上传时必须带相同的header
+	ContentMD5  string `json:"content_md5"`  // 绑定Content-MD5(base64), 客户端上传时必须带相同的header
+	UploadId    string `json:"upload_id"`    // 与PartNumber一起预签名UploadPart
+	PartNumber  int    `json:"part_number"`  // 分片号(从1开始), UploadId不为空时有效且method必须为PUT
+}
+
+// PutOptions 上传对象的可选设置, 用于PutObject或InitiateMultipartUpload
+type PutOptions struct {
+	ContentType        string            // 内容类型, 默认StorageConfig.ContentType
+	CacheControl       string            // Cache-Control
+	ContentDisposition string            // Content-Disposition
+	ContentEncoding    string            // Content-Encoding
+	Expires            string            // Expires
+	Metadata           map[string]string // 用户元数据, key自动添加profile的MetaHeaderPrefix
+	Tags               []*Tag            // 对象标签, 按URL编码通过profile的TaggingHeader发送
+}
+
+// ObjectOptions 读取/删除对象的可选设置, 用于HeadObject, GetObject或DeleteObject
+type ObjectOptions struct {
+	VersionId string // 版本ID, 为空表示当前版本. 删除指定版本为永久删除, 否则(开启多版本时)产生删除标记
+	IfMatch   string // 仅GetObject: 对象ETag与之不符时返回412(IsPreconditionFailed)
+}
+
+// 复制对象的元数据指令
+const (
+	MetadataDirectiveCopy    = "COPY"    // 复制源对象的元数据(默认)
+	MetadataDirectiveReplace = "REPLACE" // 使用CopyOptions.PutOptions替换元数据
+)
+
+// CopyOptions 复制对象的可选设置
+type CopyOptions struct {
+	MetadataDirective string // 元数据指令: COPY或REPLACE
+	SourceVersionId   string // 复制源的版本ID, 为空表示当前版本
+	PutOptions               // REPLACE时的元数据设置. Tags不受元数据指令影响, 设置时总是替换标签
 }
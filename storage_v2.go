@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -12,16 +13,17 @@ type storageV2 struct {
 	prefix  string
 	config  *StorageConfig
 	profile *Profile
-	secret  []byte
 }
 
-func NewStorageV2(prefix string, c *StorageConfig, p *Profile) Storage {
+func NewStorageV2(prefix string, c *StorageConfig, p *Profile) (Storage, error) {
+	if err := p.checkSignature(V2); err != nil {
+		return nil, err
+	}
 	s := new(storageV2)
 	s.prefix = prefix
 	s.config = c
 	s.profile = p
-	s.secret = []byte(c.Secret)
-	return s
+	return s, nil
 }
 
 func (c storageV2) Url(ctx *ProviderContext) string {
@@ -32,23 +34,23 @@ func (c storageV2) Url(ctx *ProviderContext) string {
 	// 拼接结果
 	bf.WriteString(c.profile.Schema)
 	bf.WriteString("://")
-	bf.WriteString(c.config.Domain)
+	bf.WriteString(bucketDomain(ctx, c.profile, c.config))
 	bf.WriteByte('/')
 	if c.profile.AccessBucketURI {
-		bf.WriteString(c.config.Bucket)
+		bf.WriteString(bucketName(ctx, c.config))
 		bf.WriteByte('/')
 	}
-	bf.WriteString(ctx.ObjectKey)
-	if ctx.SignedQueries.Len() > 0 {
-		bf.WriteByte('?')
-		for i, v := range ctx.SignedQueries.values {
-			if i > 0 {
-				bf.WriteByte('&')
-			}
-			bf.WriteString(v.Name)
+	bf.WriteString(UriEncode(ctx.ObjectKey, false))
+	// 签名参数与非签名参数(子资源以外的参数)都需要拼接到url
+	sep := byte('?')
+	for _, vs := range []*Values{&ctx.SignedQueries, &ctx.Queries} {
+		for _, v := range vs.values {
+			bf.WriteByte(sep)
+			sep = '&'
+			bf.WriteString(UriEncode(v.Name, true))
 			if v.Text != "" {
 				bf.WriteByte('=')
-				bf.WriteString(v.Text) // 在此项目所有参数不用escape!!!
+				bf.WriteString(UriEncode(v.Text, true)) // prefix,continuation-token等参数需要escape
 			}
 		}
 	}
@@ -63,17 +65,17 @@ func (c storageV2) Link(ctx *ProviderContext, expires, signature string) string
 	// 拼接结果
 	bf.WriteString(c.profile.Schema)
 	bf.WriteString("://")
-	bf.WriteString(c.config.Domain)
+	bf.WriteString(bucketDomain(ctx, c.profile, c.config))
 	bf.WriteByte('/')
 	if c.profile.AccessBucketURI {
-		bf.WriteString(c.config.Bucket)
+		bf.WriteString(bucketName(ctx, c.config))
 		bf.WriteByte('/')
 	}
-	bf.WriteString(ctx.ObjectKey)
+	bf.WriteString(UriEncode(ctx.ObjectKey, false))
 	bf.WriteByte('?')
 	bf.WriteString(c.profile.V2QueryParams.AccessKeyId)
 	bf.WriteByte('=')
-	bf.WriteString(url.QueryEscape(c.config.Access))
+	bf.WriteString(url.QueryEscape(c.credentials(ctx).Access))
 	bf.WriteByte('&')
 	bf.WriteString(c.profile.V2QueryParams.Expires)
 	bf.WriteByte('=')
@@ -82,6 +84,13 @@ func (c storageV2) Link(ctx *ProviderContext, expires, signature string) string
 	bf.WriteString(c.profile.V2QueryParams.Signature)
 	bf.WriteByte('=')
 	bf.WriteString(url.QueryEscape(signature))
+	// 作为子资源签名的安全令牌已在SignedQueries中
+	if token := c.credentials(ctx).Token; token != "" && !c.profile.V2TokenSubResource {
+		bf.WriteByte('&')
+		bf.WriteString(c.profile.V2QueryParams.SecurityToken)
+		bf.WriteByte('=')
+		bf.WriteString(url.QueryEscape(token))
+	}
 	for _, v := range ctx.SignedQueries.values {
 		bf.WriteByte('&')
 		bf.WriteString(v.Name)
@@ -103,6 +112,9 @@ func (c storageV2) Header(ctx *ProviderContext, signature string) map[string]str
 			ret[v.Name] = v.Text
 		}
 	}
+	for _, v := range ctx.Headers.values {
+		ret[v.Name] = v.Text
+	}
 	if ctx.Range.Start != 0 || ctx.Range.End != 0 {
 		ret[headerRange] = ctx.Range.Value()
 	}
@@ -112,7 +124,7 @@ func (c storageV2) Header(ctx *ProviderContext, signature string) map[string]str
 	if ctx.ContentMD5 != "" {
 		ret[headerContentMD5] = ctx.ContentMD5
 	}
-	ret[headerAuthorization] = c.profile.V2Code + " " + c.config.Access + ":" + signature
+	ret[headerAuthorization] = c.profile.V2Code + " " + c.credentials(ctx).Access + ":" + signature
 	return ret
 }
 
@@ -144,9 +156,9 @@ func (c storageV2) Signature(ctx *ProviderContext, date string) string {
 		bf.WriteByte('\n')
 	}
 	bf.WriteByte('/')
-	bf.WriteString(c.config.Bucket)
+	bf.WriteString(bucketName(ctx, c.config))
 	bf.WriteByte('/')
-	bf.WriteString(ctx.ObjectKey)
+	bf.WriteString(c.signedKey(ctx))
 	if ctx.SignedQueries.Len() > 0 {
 		for i, v := range ctx.SignedQueries.SortedValues() {
 			if i > 0 {
@@ -154,6 +166,14 @@ func (c storageV2) Signature(ctx *ProviderContext, date string) string {
 			} else {
 				bf.WriteByte('?')
 			}
+			if c.profile.SignedRawQuery {
+				bf.WriteString(v.Name)
+				if v.Text != "" {
+					bf.WriteByte('=')
+					bf.WriteString(v.Text)
+				}
+				continue
+			}
 			bf.WriteString(url.PathEscape(v.Name))
 			if v.Text != "" {
 				bf.WriteByte('=')
@@ -165,12 +185,71 @@ func (c storageV2) Signature(ctx *ProviderContext, date string) string {
 	/*
 		Signature = Base64(HMAC-SHA1(YourSecretKey, UTF-8-Encoding-Of( StringToSign ) ) );
 	*/
-	signature := base64.StdEncoding.EncodeToString(HmacSha1(c.secret, bf.Bytes()))
+	signature := base64.StdEncoding.EncodeToString(HmacSha1([]byte(c.credentials(ctx).Secret), bf.Bytes()))
 
 	return signature
 }
 
-func (c storageV2) PutObject(key string, contentMD5 string) *RequestSetting {
+// signedKey 签名使用的key. 默认与url一致按RFC3986编码(保留'/'), 部分云厂(见Profile.SignedRawKey)签名未编码的key
+func (c storageV2) signedKey(ctx *ProviderContext) string {
+	if c.profile.SignedRawKey {
+		return ctx.ObjectKey
+	}
+	return UriEncode(ctx.ObjectKey, false)
+}
+
+// credentials 当前请求的凭证(首次调用时读取配置, 使用provider时为OSSI绑定的本次凭证)
+func (c storageV2) credentials(ctx *ProviderContext) *Credentials {
+	if ctx.Credentials == nil {
+		ctx.Credentials = configCredentials(c.config)
+	}
+	return ctx.Credentials
+}
+
+// withCredentials 绑定本次获取的凭证, 返回使用该凭证签名的storage(复制配置, 不修改调用方的StorageConfig)
+func (c storageV2) withCredentials(cred *Credentials) Storage {
+	config := *c.config
+	config.Access, config.Secret, config.Token, config.Credentials = cred.Access, cred.Secret, cred.Token, nil
+	c.config = &config
+	return &c
+}
+
+// securityToken 使用临时凭证(STS)时添加安全令牌header, 并加入签名
+func (c storageV2) securityToken(ctx *ProviderContext) {
+	if token := c.credentials(ctx).Token; token != "" {
+		ctx.SignedHeaders.Add(c.profile.SecurityTokenHeader, token)
+	}
+}
+
+// putHeaders 添加上传设置. V2只签名x-*头(用户元数据), 标准头部不加入签名
+func (c storageV2) putHeaders(ctx *ProviderContext, opts *PutOptions) {
+	if opts == nil {
+		return
+	}
+	if opts.ContentType != "" {
+		ctx.ContentType = opts.ContentType
+	}
+	if opts.CacheControl != "" {
+		ctx.Headers.Add(headerCacheControl, opts.CacheControl)
+	}
+	if opts.ContentDisposition != "" {
+		ctx.Headers.Add(headerDisposition, opts.ContentDisposition)
+	}
+	if opts.ContentEncoding != "" {
+		ctx.Headers.Add(headerEncoding, opts.ContentEncoding)
+	}
+	if opts.Expires != "" {
+		ctx.Headers.Add(headerExpires, opts.Expires)
+	}
+	for k, v := range opts.Metadata {
+		ctx.SignedHeaders.Add(c.profile.MetaHeaderPrefix+strings.ToLower(k), v)
+	}
+	if len(opts.Tags) > 0 {
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader, EncodeTagging(opts.Tags))
+	}
+}
+
+func (c storageV2) PutObject(key string, contentMD5 string, opts *PutOptions) *RequestSetting {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -188,9 +267,11 @@ func (c storageV2) PutObject(key string, contentMD5 string) *RequestSetting {
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
 	for k, v := range c.profile.StorageHeaders {
 		ctx.SignedHeaders.Add(k, v)
 	}
+	c.putHeaders(ctx, opts)
 
 	// 3.计算signature(是否签名Date由profile决定)
 	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
@@ -204,7 +285,7 @@ func (c storageV2) PutObject(key string, contentMD5 string) *RequestSetting {
 	}
 }
 
-func (c storageV2) HeadObject(key string) *RequestSetting {
+func (c storageV2) HeadObject(key string, opts *ObjectOptions) *RequestSetting {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -221,6 +302,10 @@ func (c storageV2) HeadObject(key string) *RequestSetting {
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
 
 	// 3.计算signature(是否签名Date由profile决定)
 	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
@@ -234,7 +319,7 @@ func (c storageV2) HeadObject(key string) *RequestSetting {
 	}
 }
 
-func (c storageV2) GetObject(key string, _range *Range) *RequestSetting {
+func (c storageV2) GetObject(key string, _range *Range, opts *ObjectOptions) *RequestSetting {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -257,6 +342,13 @@ func (c storageV2) GetObject(key string, _range *Range) *RequestSetting {
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+	if opts != nil && opts.IfMatch != "" {
+		ctx.Headers.Add(headerIfMatch, opts.IfMatch)
+	}
 
 	// 3.计算signature(是否签名Date由profile决定)
 	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
@@ -271,6 +363,10 @@ func (c storageV2) GetObject(key string, _range *Range) *RequestSetting {
 }
 
 func (c storageV2) GetObjectLink(key string, timeout int64) string {
+	return c.PresignURL(http.MethodGet, key, timeout, nil)
+}
+
+func (c storageV2) PresignURL(method string, key string, timeout int64, opts *PresignOptions) string {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -280,12 +376,26 @@ func (c storageV2) GetObjectLink(key string, timeout int64) string {
 
 	// 1.初始(重置)context
 	ctx.UTC = time.Now().UTC()
-	ctx.Method = http.MethodGet
+	ctx.Method = method
 	ctx.ObjectKey = key
 	ctx.Status = http.StatusOK
+	if opts != nil {
+		ctx.ContentType = opts.ContentType
+		ctx.ContentMD5 = opts.ContentMD5
+		if opts.UploadId != "" {
+			ctx.SignedQueries.Add("partNumber", strconv.Itoa(opts.PartNumber))
+			ctx.SignedQueries.Add("uploadId", opts.UploadId)
+		}
+	}
 
+	// 2.以Expires代替Date. 安全令牌通过query发送, 按profile作为子资源或x-*头加入签名
 	exptime := ctx.UTC.Add(time.Duration(timeout) * time.Second)
 	expires := strconv.FormatInt(exptime.Unix(), 10)
+	if token := c.credentials(ctx).Token; token != "" && c.profile.V2TokenSubResource {
+		ctx.SignedQueries.Add(c.profile.V2QueryParams.SecurityToken, token)
+	} else {
+		c.securityToken(ctx)
+	}
 
 	// 3.计算signature
 	signature := c.Signature(ctx, expires)
@@ -294,7 +404,31 @@ func (c storageV2) GetObjectLink(key string, timeout int64) string {
 	return c.Link(ctx, expires, signature)
 }
 
-func (c storageV2) DeleteObject(key string) *RequestSetting {
+// PostPolicy 表单上传: Signature = Base64(HMAC-SHA1(SecretKey, Base64(policy)))
+func (c storageV2) PostPolicy(policy *PostPolicy) *PostForm {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	cred := c.credentials(ctx)
+
+	// 2.添加策略条件. 安全令牌需要同时加入条件与表单
+	b := newPostPolicyBuilder(c.prefix, c.config.Bucket, policy)
+	if cred.Token != "" {
+		b.add(c.profile.V2PostFields.SecurityToken, cred.Token)
+	}
+	_, policy64 := b.encode(ctx.UTC.Add(NvlD(policy.Expires, defaultPostPolicyExpires)))
+
+	// 3.计算signature
+	b.fields[c.profile.V2PostFields.AccessKeyId] = cred.Access
+	b.fields[c.profile.V2PostFields.Signature] = base64.StdEncoding.EncodeToString(HmacSha1([]byte(cred.Secret), UnsafeBytes(policy64)))
+
+	// 4.组装表单
+	return &PostForm{Url: postUrl(c.profile, c.config), Fields: b.fields}
+}
+
+func (c storageV2) DeleteObject(key string, opts *ObjectOptions) *RequestSetting {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -311,6 +445,10 @@ func (c storageV2) DeleteObject(key string) *RequestSetting {
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
 
 	// 3.计算signature(是否签名Date由profile决定)
 	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
@@ -324,7 +462,7 @@ func (c storageV2) DeleteObject(key string) *RequestSetting {
 	}
 }
 
-func (c storageV2) InitiateMultipartUpload(key string) *RequestSetting {
+func (c storageV2) InitiateMultipartUpload(key string, opts *PutOptions) *RequestSetting {
 	if c.prefix != "" {
 		key = c.prefix + key
 	}
@@ -341,9 +479,11 @@ func (c storageV2) InitiateMultipartUpload(key string) *RequestSetting {
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
 	for k, v := range c.profile.StorageHeaders {
 		ctx.SignedHeaders.Add(k, v)
 	}
+	c.putHeaders(ctx, opts)
 	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
 	ctx.SignedQueries.Add("uploads", "1")
 
@@ -378,6 +518,7 @@ func (c storageV2) UploadPart(key string, uploadId string, partNumber int, conte
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
 	ctx.SignedQueries.Add("partNumber", strconv.Itoa(partNumber))
 	ctx.SignedQueries.Add("uploadId", uploadId)
 
@@ -410,6 +551,7 @@ func (c storageV2) CompleteMultipartUpload(key string, uploadId string) *Request
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
 	ctx.SignedQueries.Add("uploadId", uploadId)
 
 	// 3.计算signature(是否签名Date由profile决定)
@@ -441,7 +583,784 @@ func (c storageV2) AbortMultipartUpload(key string, uploadId string) *RequestSet
 	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
 	gmt := ctx.UTC.Format(gmtDateTime)
 	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) CopyObject(srcKey string, dstKey string, opts *CopyOptions) *RequestSetting {
+	if c.prefix != "" {
+		srcKey = c.prefix + srcKey
+		dstKey = c.prefix + dstKey
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = dstKey
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	for k, v := range c.profile.StorageHeaders {
+		ctx.SignedHeaders.Add(k, v)
+	}
+	var srcVersionId string
+	if opts != nil {
+		srcVersionId = opts.SourceVersionId
+	}
+	ctx.SignedHeaders.Add(c.profile.CopySourceHeader, CopySource(c.config.Bucket, srcKey, srcVersionId))
+	if opts != nil && opts.MetadataDirective != "" {
+		ctx.SignedHeaders.Add(c.profile.DirectiveHeader, opts.MetadataDirective)
+		if opts.MetadataDirective == MetadataDirectiveReplace {
+			ctx.ContentType = c.config.ContentType
+			put := opts.PutOptions
+			put.Tags = nil // 标签由标签指令单独处理
+			c.putHeaders(ctx, &put)
+		}
+	}
+	if opts != nil && len(opts.Tags) > 0 {
+		// 复制默认保留源对象的标签, 替换标签需要标签指令(与元数据指令无关)
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader, EncodeTagging(opts.Tags))
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader+"-directive", MetadataDirectiveReplace)
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) UploadPartCopy(srcKey string, srcVersionId string, dstKey string, uploadId string, partNumber int, _range *Range) *RequestSetting {
+	if c.prefix != "" {
+		srcKey = c.prefix + srcKey
+		dstKey = c.prefix + dstKey
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = dstKey
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	ctx.SignedHeaders.Add(c.profile.CopySourceHeader, CopySource(c.config.Bucket, srcKey, srcVersionId))
+	if _range != nil {
+		ctx.SignedHeaders.Add(c.profile.CopyRangeHeader, _range.Value())
+	}
+	ctx.SignedQueries.Add("partNumber", strconv.Itoa(partNumber))
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) DeleteObjects(contentMD5 string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPost
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("delete", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) ListParts(key string, uploadId string, partNumberMarker int, maxParts int) *RequestSetting {
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
 	ctx.SignedQueries.Add("uploadId", uploadId)
+	// V2只签名子资源, 分页参数不加入签名
+	if partNumberMarker > 0 {
+		ctx.Queries.Add("part-number-marker", strconv.Itoa(partNumberMarker))
+	}
+	if maxParts > 0 {
+		ctx.Queries.Add("max-parts", strconv.Itoa(maxParts))
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) ListMultipartUploads(prefix string, keyMarker string, uploadIdMarker string, maxUploads int) *RequestSetting {
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+		if keyMarker != "" {
+			keyMarker = c.prefix + keyMarker
+		}
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("uploads", "1")
+	// V2只签名子资源, 列举参数不加入签名
+	if prefix != "" {
+		ctx.Queries.Add("prefix", prefix)
+	}
+	if keyMarker != "" {
+		ctx.Queries.Add("key-marker", keyMarker)
+	}
+	if uploadIdMarker != "" {
+		ctx.Queries.Add("upload-id-marker", uploadIdMarker)
+	}
+	if maxUploads > 0 {
+		ctx.Queries.Add("max-uploads", strconv.Itoa(maxUploads))
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) ListObjects(prefix string, delimiter string, continuationToken string, maxKeys int) *RequestSetting {
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// V2只签名子资源, 列举参数不加入签名
+	ctx.Queries.Add("list-type", "2")
+	if prefix != "" {
+		ctx.Queries.Add("prefix", prefix)
+	}
+	if delimiter != "" {
+		ctx.Queries.Add("delimiter", delimiter)
+	}
+	if continuationToken != "" {
+		ctx.Queries.Add("continuation-token", continuationToken)
+	}
+	if maxKeys > 0 {
+		ctx.Queries.Add("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) ListObjectVersions(prefix string, delimiter string, keyMarker string, versionIdMarker string, maxKeys int) *RequestSetting {
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+		if keyMarker != "" {
+			keyMarker = c.prefix + keyMarker
+		}
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("versions", "1")
+	// V2只签名子资源, 列举参数不加入签名
+	if prefix != "" {
+		ctx.Queries.Add("prefix", prefix)
+	}
+	if delimiter != "" {
+		ctx.Queries.Add("delimiter", delimiter)
+	}
+	if keyMarker != "" {
+		ctx.Queries.Add("key-marker", keyMarker)
+	}
+	if versionIdMarker != "" {
+		ctx.Queries.Add("version-id-marker", versionIdMarker)
+	}
+	if maxKeys > 0 {
+		ctx.Queries.Add("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) PutObjectTagging(key string, contentMD5 string, opts *ObjectOptions) *RequestSetting {
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("tagging", "1")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) GetObjectTagging(key string, opts *ObjectOptions) *RequestSetting {
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("tagging", "1")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) DeleteObjectTagging(key string, opts *ObjectOptions) *RequestSetting {
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("tagging", "1")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) CreateBucket(bucket string, acl string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	if acl != "" {
+		ctx.SignedHeaders.Add(c.profile.AclHeader, acl)
+	}
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) HeadBucket(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodHead
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) DeleteBucket(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) GetBucketLocation(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("location", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) PutBucketLifecycle(bucket string, contentMD5 string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("lifecycle", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) GetBucketLifecycle(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("lifecycle", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) DeleteBucketLifecycle(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("lifecycle", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) PutBucketCors(bucket string, contentMD5 string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("cors", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) GetBucketCors(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("cors", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) DeleteBucketCors(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("cors", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) PutBucketVersioning(bucket string, contentMD5 string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("versioning", "1")
+
+	// 3.计算signature(是否签名Date由profile决定)
+	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, signature),
+	}
+}
+
+func (c storageV2) GetBucketVersioning(bucket string) *RequestSetting {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加Date及profile的设置.其中Date使用profile定义的名称
+	gmt := ctx.UTC.Format(gmtDateTime)
+	ctx.SignedHeaders.Add(c.profile.DateHeader, gmt)
+	c.securityToken(ctx)
+	// 阿里云签名对于无值参数不需"=", 金山云签名对于无值参数需要"=". 这里带上"1"兼容二边的签名!
+	ctx.SignedQueries.Add("versioning", "1")
 
 	// 3.计算signature(是否签名Date由profile决定)
 	signature := c.Signature(ctx, If(c.profile.SignedDateHeader, gmt, ""))
@@ -457,3 +1376,4 @@ func (c storageV2) AbortMultipartUpload(key string, uploadId string) *RequestSet
 
 var _ SignatureV2 = (*storageV2)(nil)
 var _ Storage = (*storageV2)(nil)
+var _ BucketStorage = (*storageV2)(nil)
@@ -0,0 +1,1437 @@
+package oss
+
+import (
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultV5Expires V5签名用于header认证时的有效期
+const defaultV5Expires = 15 * time.Minute
+
+/*
+storageV5 腾讯云COS的签名算法(q-sign-algorithm=sha1)
+与V2/V4不同, COS使用KeyTime(起止unix时间)代替Date, 所有query与header均可加入签名.
+*/
+type storageV5 struct {
+	prefix  string
+	config  *StorageConfig
+	profile *Profile
+}
+
+func NewStorageV5(prefix string, c *StorageConfig, p *Profile) (Storage, error) {
+	if err := p.checkSignature(V5); err != nil {
+		return nil, err
+	}
+	s := new(storageV5)
+	s.prefix = prefix
+	s.config = c
+	s.profile = p
+	return s, nil
+}
+
+func (c storageV5) Url(ctx *ProviderContext) string {
+	// 重用buffer
+	bf := borrowBuffer()
+	defer returnBuffer(bf)
+
+	// 拼接结果
+	bf.WriteString(c.profile.Schema)
+	bf.WriteString("://")
+	bf.WriteString(bucketDomain(ctx, c.profile, c.config))
+	bf.WriteByte('/')
+	if c.profile.AccessBucketURI {
+		bf.WriteString(bucketName(ctx, c.config))
+		bf.WriteByte('/')
+	}
+	bf.WriteString(UriEncode(ctx.ObjectKey, false))
+	if ctx.SignedQueries.Len() > 0 {
+		bf.WriteByte('?')
+		for i, v := range ctx.SignedQueries.values {
+			if i > 0 {
+				bf.WriteByte('&')
+			}
+			bf.WriteString(UriEncode(v.Name, true))
+			if v.Text != "" {
+				bf.WriteByte('=')
+				bf.WriteString(UriEncode(v.Text, true)) // prefix,marker等参数需要escape
+			}
+		}
+	}
+	return bf.String()
+}
+
+func (c storageV5) Link(ctx *ProviderContext, keyTime string, signature string) string {
+	link := c.Url(ctx)
+	if ctx.SignedQueries.Len() > 0 {
+		return link + "&" + c.authorization(ctx, keyTime, signature)
+	}
+	return link + "?" + c.authorization(ctx, keyTime, signature)
+}
+
+func (c storageV5) Header(ctx *ProviderContext, keyTime string, signature string) map[string]string {
+	var ret = make(map[string]string)
+	for _, v := range ctx.SignedHeaders.values {
+		if v.Name == headerHost {
+			ret["Host"] = v.Text
+		} else {
+			ret[v.Name] = v.Text
+		}
+	}
+	if ctx.Range.Start != 0 || ctx.Range.End != 0 {
+		ret[headerRange] = ctx.Range.Value()
+	}
+	ret[headerAuthorization] = c.authorization(ctx, keyTime, signature)
+	return ret
+}
+
+/*
+Signature
+V5签名必须注意
+# SignKey = HMAC-SHA1(SecretKey, KeyTime)
+# query与header的名称小写, 名称与值均需UriEncode, 再按名称升序
+# HttpString = method(小写) + '\n' + UriPathname + '\n' + HttpParameters + '\n' + HttpHeaders + '\n'
+# StringToSign = "sha1" + '\n' + KeyTime + '\n' + SHA1(HttpString) + '\n'
+*/
+func (c storageV5) Signature(ctx *ProviderContext, keyTime string) (signature string) {
+
+	// 重用buffer
+	bf := borrowBuffer()
+	defer returnBuffer(bf)
+
+	// HttpString
+	bf.WriteString(strings.ToLower(ctx.Method))
+	bf.WriteByte('\n')
+	if c.profile.SignedBucketURI {
+		bf.WriteByte('/')
+		bf.WriteString(bucketName(ctx, c.config))
+	}
+	bf.WriteByte('/')
+	bf.WriteString(c.signedKey(ctx))
+	bf.WriteByte('\n')
+	for i, v := range encodedValues(&ctx.SignedQueries) {
+		if i > 0 {
+			bf.WriteByte('&')
+		}
+		bf.WriteString(v.Name)
+		bf.WriteByte('=')
+		bf.WriteString(v.Text)
+	}
+	bf.WriteByte('\n')
+	for i, v := range encodedValues(&ctx.SignedHeaders) {
+		if i > 0 {
+			bf.WriteByte('&')
+		}
+		bf.WriteString(v.Name)
+		bf.WriteByte('=')
+		bf.WriteString(v.Text)
+	}
+	bf.WriteByte('\n')
+
+	// 计算HttpString的Sha1
+	httpSha1Hex := hex.EncodeToString(Sha1(bf.Bytes()))
+
+	// 重置清零
+	bf.Reset()
+	bf.WriteString(c.profile.V5Algorithm)
+	bf.WriteByte('\n')
+	bf.WriteString(keyTime)
+	bf.WriteByte('\n')
+	bf.WriteString(httpSha1Hex)
+	bf.WriteByte('\n')
+
+	// 注意: SignKey是hex字符串而非原始字节
+	signKey := hex.EncodeToString(HmacSha1([]byte(c.credentials(ctx).Secret), UnsafeBytes(keyTime)))
+	signature = hex.EncodeToString(HmacSha1(UnsafeBytes(signKey), bf.Bytes()))
+	return
+}
+
+// keyTime 签名有效期: 开始与结束的unix秒数, 以';'分隔
+func (c storageV5) keyTime(start time.Time, expires time.Duration) string {
+	return strconv.FormatInt(start.Unix(), 10) + ";" + strconv.FormatInt(start.Add(expires).Unix(), 10)
+}
+
+func (c storageV5) signedHeaders(ctx *ProviderContext) {
+	// 添加必需的header
+	if ctx.ContentType != "" {
+		ctx.SignedHeaders.Add(headerContentType, ctx.ContentType)
+	}
+	if ctx.ContentMD5 != "" {
+		ctx.SignedHeaders.Add(headerContentMD5, ctx.ContentMD5)
+	}
+	ctx.SignedHeaders.Add(headerHost, bucketDomain(ctx, c.profile, c.config))
+}
+
+/*
+authorization 组装认证串, 用于Authorization头或者下载外链的query
+q-sign-algorithm=sha1&q-ak=<ak>&q-sign-time=<KeyTime>&q-key-time=<KeyTime>&q-header-list=<HeaderList>&q-url-param-list=<UrlParamList>&q-signature=<signature>
+*/
+func (c storageV5) authorization(ctx *ProviderContext, keyTime string, signature string) string {
+	bf := borrowBuffer()
+	defer returnBuffer(bf)
+
+	bf.WriteString("q-sign-algorithm=")
+	bf.WriteString(c.profile.V5Algorithm)
+	bf.WriteString("&q-ak=")
+	bf.WriteString(c.credentials(ctx).Access)
+	bf.WriteString("&q-sign-time=")
+	bf.WriteString(keyTime)
+	bf.WriteString("&q-key-time=")
+	bf.WriteString(keyTime)
+	bf.WriteString("&q-header-list=")
+	for i, v := range encodedValues(&ctx.SignedHeaders) {
+		if i > 0 {
+			bf.WriteByte(';')
+		}
+		bf.WriteString(v.Name)
+	}
+	bf.WriteString("&q-url-param-list=")
+	for i, v := range encodedValues(&ctx.SignedQueries) {
+		if i > 0 {
+			bf.WriteByte(';')
+		}
+		bf.WriteString(v.Name)
+	}
+	bf.WriteString("&q-signature=")
+	bf.WriteString(signature)
+	return bf.String()
+}
+
+// encodedValues 名称小写后名称与值均UriEncode, 再按名称升序. 不修改原Values
+func encodedValues(p *Values) []*Value {
+	ret := make([]*Value, 0, p.Len())
+	for _, v := range p.values {
+		ret = append(ret, &Value{
+			Name: UriEncode(strings.ToLower(v.Name), true),
+			Text: UriEncode(v.Text, true),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		if ret[i].Name == ret[j].Name {
+			return ret[i].Text < ret[j].Text
+		}
+		return ret[i].Name < ret[j].Name
+	})
+	return ret
+}
+
+// copySource COS的复制源是<Domain>/<Key>, 与S3的/<Bucket>/<Key>不同
+func (c storageV5) copySource(key string, versionId string) string {
+	source := c.config.Domain + "/" + UriEncode(key, false)
+	if c.profile.AccessBucketURI {
+		source = c.config.Domain + "/" + c.config.Bucket + "/" + UriEncode(key, false)
+	}
+	if versionId != "" {
+		source += "?versionId=" + url.QueryEscape(versionId)
+	}
+	return source
+}
+
+// signedKey 签名使用的key. 默认与url一致按RFC3986编码(保留'/'), 部分云厂(见Profile.SignedRawKey)签名未编码的key
+func (c storageV5) signedKey(ctx *ProviderContext) string {
+	if c.profile.SignedRawKey {
+		return ctx.ObjectKey
+	}
+	return UriEncode(ctx.ObjectKey, false)
+}
+
+// credentials 当前请求的凭证(首次调用时读取配置, 使用provider时为OSSI绑定的本次凭证)
+func (c storageV5) credentials(ctx *ProviderContext) *Credentials {
+	if ctx.Credentials == nil {
+		ctx.Credentials = configCredentials(c.config)
+	}
+	return ctx.Credentials
+}
+
+// withCredentials 绑定本次获取的凭证, 返回使用该凭证签名的storage(复制配置, 不修改调用方的StorageConfig)
+func (c storageV5) withCredentials(cred *Credentials) Storage {
+	config := *c.config
+	config.Access, config.Secret, config.Token, config.Credentials = cred.Access, cred.Secret, cred.Token, nil
+	c.config = &config
+	return &c
+}
+
+// securityToken 使用临时凭证(STS)时添加安全令牌header, 并加入签名
+func (c storageV5) securityToken(ctx *ProviderContext) {
+	if token := c.credentials(ctx).Token; token != "" {
+		ctx.SignedHeaders.Add(c.profile.SecurityTokenHeader, token)
+	}
+}
+
+// putHeaders 添加上传设置. V5全部加入签名
+func (c storageV5) putHeaders(ctx *ProviderContext, opts *PutOptions) {
+	if opts == nil {
+		return
+	}
+	if opts.ContentType != "" {
+		ctx.ContentType = opts.ContentType
+	}
+	if opts.CacheControl != "" {
+		ctx.SignedHeaders.Add(headerCacheControl, opts.CacheControl)
+	}
+	if opts.ContentDisposition != "" {
+		ctx.SignedHeaders.Add(headerDisposition, opts.ContentDisposition)
+	}
+	if opts.ContentEncoding != "" {
+		ctx.SignedHeaders.Add(headerEncoding, opts.ContentEncoding)
+	}
+	if opts.Expires != "" {
+		ctx.SignedHeaders.Add(headerExpires, opts.Expires)
+	}
+	for k, v := range opts.Metadata {
+		ctx.SignedHeaders.Add(c.profile.MetaHeaderPrefix+strings.ToLower(k), v)
+	}
+	if len(opts.Tags) > 0 {
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader, EncodeTagging(opts.Tags))
+	}
+}
+
+func (c storageV5) PutObject(key string, contentMD5 string, opts *PutOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+	ctx.ContentType = c.config.ContentType
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	for k, v := range c.profile.StorageHeaders {
+		ctx.SignedHeaders.Add(k, v)
+	}
+	c.putHeaders(ctx, opts)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) HeadObject(key string, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodHead
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetObject(key string, _range *Range, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = key
+	if _range != nil && (_range.Start != 0 || _range.End != 0) {
+		ctx.Status = http.StatusPartialContent
+		ctx.Range.Start = _range.Start
+		ctx.Range.End = _range.End
+	} else {
+		ctx.Status = http.StatusOK
+	}
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+	if opts != nil && opts.IfMatch != "" {
+		ctx.SignedHeaders.Add(headerIfMatch, opts.IfMatch)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetObjectLink(key string, timeout int64) string {
+	return c.PresignURL(http.MethodGet, key, timeout, nil)
+}
+
+func (c storageV5) PresignURL(method string, key string, timeout int64, opts *PresignOptions) string {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = method
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+	if opts != nil {
+		ctx.ContentType = opts.ContentType
+		ctx.ContentMD5 = opts.ContentMD5
+		if opts.UploadId != "" {
+			ctx.SignedQueries.Add("partNumber", strconv.Itoa(opts.PartNumber))
+			ctx.SignedQueries.Add("uploadId", opts.UploadId)
+		}
+	}
+
+	// 2.添加profile的设置. 外链的有效期即KeyTime, 安全令牌通过query发送
+	keyTime := c.keyTime(ctx.UTC, time.Duration(timeout)*time.Second)
+	if token := c.credentials(ctx).Token; token != "" {
+		ctx.SignedQueries.Add(c.profile.SecurityTokenHeader, token)
+	}
+
+	// 3.计算signature
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return c.Link(ctx, keyTime, signature)
+}
+
+// PostPolicy 表单上传: StringToSign = Hex(SHA1(policy)), Signature = Hex(HMAC-SHA1(SignKey, StringToSign))
+func (c storageV5) PostPolicy(policy *PostPolicy) *PostForm {
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	cred := c.credentials(ctx)
+
+	// 2.添加策略条件. 算法,ak,KeyTime及安全令牌需要同时加入条件与表单
+	expires := NvlD(policy.Expires, defaultPostPolicyExpires)
+	keyTime := c.keyTime(ctx.UTC, expires)
+	b := newPostPolicyBuilder(c.prefix, c.config.Bucket, policy)
+	b.add("q-sign-algorithm", c.profile.V5Algorithm)
+	b.add("q-ak", cred.Access)
+	b.add("q-sign-time", keyTime)
+	if cred.Token != "" {
+		b.add(c.profile.SecurityTokenHeader, cred.Token)
+	}
+	raw, _ := b.encode(ctx.UTC.Add(expires))
+
+	// 3.计算signature
+	b.fields["q-key-time"] = keyTime
+	signKey := hex.EncodeToString(HmacSha1([]byte(cred.Secret), UnsafeBytes(keyTime)))
+	b.fields["q-signature"] = hex.EncodeToString(HmacSha1(UnsafeBytes(signKey), UnsafeBytes(hex.EncodeToString(Sha1(raw)))))
+
+	// 4.组装表单
+	return &PostForm{Url: postUrl(c.profile, c.config), Fields: b.fields}
+}
+
+func (c storageV5) DeleteObject(key string, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) InitiateMultipartUpload(key string, opts *PutOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPost
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	for k, v := range c.profile.StorageHeaders {
+		ctx.SignedHeaders.Add(k, v)
+	}
+	c.putHeaders(ctx, opts)
+	// V5签名对于无值参数按"uploads="计算, 无需兼容处理
+	ctx.SignedQueries.Add("uploads", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) UploadPart(key string, uploadId string, partNumber int, contentMD5 string) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+	ctx.ContentType = c.config.ContentType
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("partNumber", strconv.Itoa(partNumber))
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) CompleteMultipartUpload(key string, uploadId string) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPost
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) AbortMultipartUpload(key string, uploadId string) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) CopyObject(srcKey string, dstKey string, opts *CopyOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		srcKey = c.prefix + srcKey
+		dstKey = c.prefix + dstKey
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = dstKey
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	for k, v := range c.profile.StorageHeaders {
+		ctx.SignedHeaders.Add(k, v)
+	}
+	var srcVersionId string
+	if opts != nil {
+		srcVersionId = opts.SourceVersionId
+	}
+	ctx.SignedHeaders.Add(c.profile.CopySourceHeader, c.copySource(srcKey, srcVersionId))
+	if opts != nil && opts.MetadataDirective != "" {
+		ctx.SignedHeaders.Add(c.profile.DirectiveHeader, opts.MetadataDirective)
+		if opts.MetadataDirective == MetadataDirectiveReplace {
+			ctx.ContentType = c.config.ContentType
+			put := opts.PutOptions
+			put.Tags = nil // 标签由标签指令单独处理
+			c.putHeaders(ctx, &put)
+		}
+	}
+	if opts != nil && len(opts.Tags) > 0 {
+		// 复制默认保留源对象的标签, 替换标签需要标签指令(与元数据指令无关)
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader, EncodeTagging(opts.Tags))
+		ctx.SignedHeaders.Add(c.profile.TaggingHeader+"-directive", MetadataDirectiveReplace)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) UploadPartCopy(srcKey string, srcVersionId string, dstKey string, uploadId string, partNumber int, _range *Range) *RequestSetting {
+
+	if c.prefix != "" {
+		srcKey = c.prefix + srcKey
+		dstKey = c.prefix + dstKey
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = dstKey
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedHeaders.Add(c.profile.CopySourceHeader, c.copySource(srcKey, srcVersionId))
+	if _range != nil {
+		ctx.SignedHeaders.Add(c.profile.CopyRangeHeader, _range.Value())
+	}
+	ctx.SignedQueries.Add("partNumber", strconv.Itoa(partNumber))
+	ctx.SignedQueries.Add("uploadId", uploadId)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) DeleteObjects(contentMD5 string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPost
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("delete", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) ListParts(key string, uploadId string, partNumberMarker int, maxParts int) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("uploadId", uploadId)
+	if partNumberMarker > 0 {
+		ctx.SignedQueries.Add("part-number-marker", strconv.Itoa(partNumberMarker))
+	}
+	if maxParts > 0 {
+		ctx.SignedQueries.Add("max-parts", strconv.Itoa(maxParts))
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) ListMultipartUploads(prefix string, keyMarker string, uploadIdMarker string, maxUploads int) *RequestSetting {
+
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+		if keyMarker != "" {
+			keyMarker = c.prefix + keyMarker
+		}
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("uploads", "")
+	if prefix != "" {
+		ctx.SignedQueries.Add("prefix", prefix)
+	}
+	if keyMarker != "" {
+		ctx.SignedQueries.Add("key-marker", keyMarker)
+	}
+	if uploadIdMarker != "" {
+		ctx.SignedQueries.Add("upload-id-marker", uploadIdMarker)
+	}
+	if maxUploads > 0 {
+		ctx.SignedQueries.Add("max-uploads", strconv.Itoa(maxUploads))
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+// ListObjects COS仅支持marker分页, continuationToken作为marker发送(即上一页的NextMarker)
+func (c storageV5) ListObjects(prefix string, delimiter string, continuationToken string, maxKeys int) *RequestSetting {
+
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	if prefix != "" {
+		ctx.SignedQueries.Add("prefix", prefix)
+	}
+	if delimiter != "" {
+		ctx.SignedQueries.Add("delimiter", delimiter)
+	}
+	if continuationToken != "" {
+		ctx.SignedQueries.Add("marker", continuationToken)
+	}
+	if maxKeys > 0 {
+		ctx.SignedQueries.Add("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) ListObjectVersions(prefix string, delimiter string, keyMarker string, versionIdMarker string, maxKeys int) *RequestSetting {
+
+	if c.prefix != "" {
+		prefix = c.prefix + prefix
+		if keyMarker != "" {
+			keyMarker = c.prefix + keyMarker
+		}
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("versions", "")
+	if prefix != "" {
+		ctx.SignedQueries.Add("prefix", prefix)
+	}
+	if delimiter != "" {
+		ctx.SignedQueries.Add("delimiter", delimiter)
+	}
+	if keyMarker != "" {
+		ctx.SignedQueries.Add("key-marker", keyMarker)
+	}
+	if versionIdMarker != "" {
+		ctx.SignedQueries.Add("version-id-marker", versionIdMarker)
+	}
+	if maxKeys > 0 {
+		ctx.SignedQueries.Add("max-keys", strconv.Itoa(maxKeys))
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) PutObjectTagging(key string, contentMD5 string, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("tagging", "")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetObjectTagging(key string, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("tagging", "")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) DeleteObjectTagging(key string, opts *ObjectOptions) *RequestSetting {
+
+	if c.prefix != "" {
+		key = c.prefix + key
+	}
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.ObjectKey = key
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("tagging", "")
+	if opts != nil && opts.VersionId != "" {
+		ctx.SignedQueries.Add("versionId", opts.VersionId)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) CreateBucket(bucket string, acl string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	if acl != "" {
+		ctx.SignedHeaders.Add(c.profile.AclHeader, acl)
+	}
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) HeadBucket(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodHead
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) DeleteBucket(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetBucketLocation(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("location", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) PutBucketLifecycle(bucket string, contentMD5 string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("lifecycle", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetBucketLifecycle(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("lifecycle", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) DeleteBucketLifecycle(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("lifecycle", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) PutBucketCors(bucket string, contentMD5 string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("cors", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetBucketCors(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("cors", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) DeleteBucketCors(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodDelete
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusNoContent
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("cors", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) PutBucketVersioning(bucket string, contentMD5 string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodPut
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+	ctx.ContentMD5 = contentMD5
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("versioning", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+func (c storageV5) GetBucketVersioning(bucket string) *RequestSetting {
+
+	ctx := borrowContext()
+	defer returnContext(ctx)
+
+	// 1.初始(重置)context
+	ctx.UTC = time.Now().UTC()
+	ctx.Method = http.MethodGet
+	ctx.Bucket = bucket
+	ctx.ObjectKey = ""
+	ctx.Status = http.StatusOK
+
+	// 2.添加profile的设置. V5的签名时间由KeyTime表示, 不需要Date
+	c.securityToken(ctx)
+	ctx.SignedQueries.Add("versioning", "")
+
+	// 3.计算keyTime, signature
+	keyTime := c.keyTime(ctx.UTC, defaultV5Expires)
+	c.signedHeaders(ctx)
+	signature := c.Signature(ctx, keyTime)
+
+	// 4.组装request
+	return &RequestSetting{
+		Method: ctx.Method,
+		Status: ctx.Status,
+		Url:    c.Url(ctx),
+		Header: c.Header(ctx, keyTime, signature),
+	}
+}
+
+var _ SignatureV5 = (*storageV5)(nil)
+var _ Storage = (*storageV5)(nil)
+var _ BucketStorage = (*storageV5)(nil)
@@ -0,0 +1,134 @@
+package oss
+
+import (
+	"encoding/hex"
+	"io"
+	"strconv"
+)
+
+const (
+	streamingChunkSize       = 64 * 1024 // 流式签名每个分块的大小
+	streamingSignatureLength = 64        // 分块签名(hex)的长度
+	streamingChunkSignature  = ";chunk-signature="
+	streamingContentEncoding = "aws-chunked"
+)
+
+// emptySha256 空内容的SHA256(hex), 用于分块签名的StringToSign
+var emptySha256 = hex.EncodeToString(Sha256(nil))
+
+/*
+ChunkSigner V4流式签名(aws-chunked)的分块签名器. 每个分块的签名串联上一个分块的签名,
+第一个分块串联请求头的签名(seed signature). 每次请求(包括重试)都必须重新创建.
+*/
+type ChunkSigner struct {
+	algorithm string // 分块签名算法, 如AWS4-HMAC-SHA256-PAYLOAD
+	datetime  string // 与请求签名相同的时间(YYYYMMDD'T'HHMMSS'Z')
+	scope     string // 与请求签名相同的scope
+	key       []byte // 与请求签名相同的signing key
+	previous  string // 上一个签名
+}
+
+func newChunkSigner(algorithm string, datetime string, scope string, key []byte, seed string) *ChunkSigner {
+	return &ChunkSigner{
+		algorithm: algorithm,
+		datetime:  datetime,
+		scope:     scope,
+		key:       key,
+		previous:  seed,
+	}
+}
+
+// sign 计算分块签名, 并作为下一个分块的PreviousSignature
+func (s *ChunkSigner) sign(chunk []byte) string {
+	/*
+		StringToSign =
+			Algorithm + \n +
+			RequestDateTime + \n +
+			CredentialScope + \n +
+			PreviousSignature + \n +
+			Hex(SHA256("")) + \n +
+			Hex(SHA256(ChunkData))
+	*/
+	bf := borrowBuffer()
+	defer returnBuffer(bf)
+
+	bf.WriteString(s.algorithm)
+	bf.WriteByte('\n')
+	bf.WriteString(s.datetime)
+	bf.WriteByte('\n')
+	bf.WriteString(s.scope)
+	bf.WriteByte('\n')
+	bf.WriteString(s.previous)
+	bf.WriteByte('\n')
+	bf.WriteString(emptySha256)
+	bf.WriteByte('\n')
+	bf.WriteString(hex.EncodeToString(Sha256(chunk)))
+
+	s.previous = hex.EncodeToString(HmacSha256(s.key, bf.Bytes()))
+	return s.previous
+}
+
+// EncodedLength 原始内容按aws-chunked编码后的长度(即Content-Length)
+func (s *ChunkSigner) EncodedLength(decodedLength int64) int64 {
+	full := decodedLength / streamingChunkSize
+	length := full * chunkLength(streamingChunkSize)
+	if remain := decodedLength % streamingChunkSize; remain > 0 {
+		length += chunkLength(remain)
+	}
+	return length + chunkLength(0) // 结束分块
+}
+
+// chunkLength 单个分块编码后的长度: hex(size);chunk-signature=<signature>\r\n<data>\r\n
+func chunkLength(size int64) int64 {
+	return int64(len(strconv.FormatInt(size, 16))+len(streamingChunkSignature)+streamingSignatureLength+2) + size + 2
+}
+
+// chunkReader 将原始内容按aws-chunked编码并逐块签名
+type chunkReader struct {
+	src    io.Reader
+	signer *ChunkSigner
+	chunk  []byte // 原始分块缓存
+	frame  []byte // 已编码未读取的部分
+	done   bool   // 已编码结束分块
+}
+
+// newChunkReader 只编码src的前decodedLength字节, 保证与EncodedLength计算的Content-Length一致
+func newChunkReader(src io.Reader, decodedLength int64, signer *ChunkSigner) *chunkReader {
+	return &chunkReader{
+		src:    io.LimitReader(src, decodedLength),
+		signer: signer,
+		chunk:  make([]byte, streamingChunkSize),
+	}
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.frame) == 0 {
+		if r.done {
+			return 0, io.EOF
+		}
+		n, err := io.ReadFull(r.src, r.chunk)
+		if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+			return 0, err
+		}
+		if n > 0 {
+			r.encode(r.chunk[:n])
+		} else {
+			r.encode(nil)
+			r.done = true
+		}
+	}
+	n := copy(p, r.frame)
+	r.frame = r.frame[n:]
+	return n, nil
+}
+
+func (r *chunkReader) encode(data []byte) {
+	frame := make([]byte, 0, chunkLength(int64(len(data))))
+	frame = strconv.AppendInt(frame, int64(len(data)), 16)
+	frame = append(frame, streamingChunkSignature...)
+	frame = append(frame, r.signer.sign(data)...)
+	frame = append(frame, '\r', '\n')
+	frame = append(frame, data...)
+	frame = append(frame, '\r', '\n')
+	r.frame = frame
+}
@@ -0,0 +1,94 @@
+package oss
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/xml"
+	"net/http"
+	"net/url"
+)
+
+// Tag 标签, 用于对象标签及生命周期规则的过滤条件
+type Tag struct {
+	Key   string `xml:"Key"`
+	Value string `xml:"Value"`
+}
+
+// Tagging 对象标签(S3的?tagging格式)
+type Tagging struct {
+	XMLName xml.Name `xml:"Tagging"`
+	TagSet  []*Tag   `xml:"TagSet>Tag"`
+}
+
+// EncodeTagging 上传时设置标签的header值, 格式为URL编码的k1=v1&k2=v2
+func EncodeTagging(tags []*Tag) string {
+	values := make(url.Values, len(tags))
+	for _, t := range tags {
+		values.Add(t.Key, t.Value)
+	}
+	return values.Encode()
+}
+
+/*
+PutObjectTagging 设置对象标签(覆盖原有标签). 开启多版本时opts可指定版本ID
+*/
+func (o *ossiImpl) PutObjectTagging(ctx context.Context, ossKey string, tags []*Tag, opts ...*ObjectOptions) error {
+	opt := firstOption(opts)
+	body, err := xml.Marshal(&Tagging{TagSet: tags})
+	if err != nil {
+		return err
+	}
+	contentMD5 := base64.StdEncoding.EncodeToString(Md5(body))
+	rsp, err := o.do(ctx, &request{
+		setting:       func(s Storage) *RequestSetting { return s.PutObjectTagging(ossKey, contentMD5, opt) },
+		body:          bytes.NewReader(body),
+		contentLength: int64(len(body)),
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+// GetObjectTagging 对象没有标签时返回空
+func (o *ossiImpl) GetObjectTagging(ctx context.Context, ossKey string, opts ...*ObjectOptions) ([]*Tag, error) {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.GetObjectTagging(ossKey, opt) },
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer discardResponseBody(rsp)
+
+	result, err := ExtractTagging(rsp)
+	if err != nil {
+		return nil, err
+	}
+	return result.TagSet, nil
+}
+
+func (o *ossiImpl) DeleteObjectTagging(ctx context.Context, ossKey string, opts ...*ObjectOptions) error {
+	opt := firstOption(opts)
+	rsp, err := o.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.DeleteObjectTagging(ossKey, opt) },
+	})
+	if err != nil {
+		return err
+	}
+	discardResponseBody(rsp)
+	return nil
+}
+
+func ExtractTagging(rsp *http.Response) (*Tagging, error) {
+
+	result := new(Tagging)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
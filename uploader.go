@@ -0,0 +1,509 @@
+package oss
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	defaultUploaderPartSize    = 8 * 1024 * 1024 // 默认分片大小
+	defaultUploaderConcurrency = 4               // 默认并发上传数
+	minUploaderPartSize        = 5 * 1024 * 1024 // S3要求除最后分片外至少5M
+	maxUploaderParts           = 10000           // S3要求分片数不超过10000
+	maxCopyObjectSize          = 5 << 30         // S3要求CopyObject不超过5G
+)
+
+var ErrTooManyParts = errors.New("too many parts")
+
+type UploaderConfig struct {
+	PartSize    int64 `json:"part_size"`   // 分片大小(默认8M, 最小5M)
+	Concurrency int   `json:"concurrency"` // 并发上传数(默认4)
+}
+
+// Uploader 基于OSSI的分片上传管理, 小于一个分片的内容直接PutObject
+type Uploader struct {
+	ossi        OSSI
+	partSize    int64
+	concurrency int
+}
+
+func NewUploader(o OSSI, c *UploaderConfig) *Uploader {
+	if c == nil {
+		c = new(UploaderConfig)
+	}
+	u := &Uploader{
+		ossi:        o,
+		partSize:    c.PartSize,
+		concurrency: NvlI(c.Concurrency, defaultUploaderConcurrency),
+	}
+	if u.partSize == 0 {
+		u.partSize = defaultUploaderPartSize
+	} else if u.partSize < minUploaderPartSize {
+		u.partSize = minUploaderPartSize
+	}
+	return u
+}
+
+/*
+Upload 上传任意io.Reader, 按分片大小切分后并发上传
+*/
+func (u *Uploader) Upload(c context.Context, ossKey string, r io.Reader, opts ...*PutOptions) error {
+	first := make([]byte, u.partSize)
+	n, err := io.ReadFull(r, first)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		// 不足一个分片直接上传
+		return u.ossi.PutObjectData(c, ossKey, first[:n], opts...)
+	}
+	if err != nil {
+		return err
+	}
+	return u.multipart(c, ossKey, r, first, u.partSize, opts)
+}
+
+/*
+UploadAt 上传已知大小的io.ReaderAt, 分片数超出限制时自动增大分片
+*/
+func (u *Uploader) UploadAt(c context.Context, ossKey string, r io.ReaderAt, size int64, opts ...*PutOptions) error {
+	if size <= u.partSize {
+		// SectionReader支持Seek, 失败可以重试
+		return u.ossi.PutObject(c, ossKey, size, io.NewSectionReader(r, 0, size), opts...)
+	}
+
+	partSize := u.partSize
+	if size > partSize*maxUploaderParts {
+		partSize = (size + maxUploaderParts - 1) / maxUploaderParts
+	}
+	sr := io.NewSectionReader(r, 0, size)
+	first := make([]byte, partSize)
+	if _, err := io.ReadFull(sr, first); err != nil {
+		return err
+	}
+	return u.multipart(c, ossKey, sr, first, partSize, opts)
+}
+
+// multipart 初始化分片上传并上传全部分片, 失败或取消时放弃上传
+func (u *Uploader) multipart(c context.Context, ossKey string, r io.Reader, first []byte, partSize int64, opts []*PutOptions) error {
+	uploadId, err := u.ossi.InitiateMultipartUpload(c, ossKey, opts...)
+	if err != nil {
+		return err
+	}
+
+	parts, err := u.uploadParts(c, ossKey, uploadId, r, first, partSize)
+	if err == nil {
+		err = u.ossi.CompleteMultipartUpload(c, ossKey, uploadId, parts)
+	}
+	if err != nil {
+		// 调用方的context可能已取消, 放弃上传不受其影响
+		u.ossi.AbortMultipartUpload(context.WithoutCancel(c), ossKey, uploadId)
+		return err
+	}
+	return nil
+}
+
+type uploadChunk struct {
+	partNumber int
+	data       []byte
+}
+
+// uploadParts 顺序读取分片, 并发上传. 返回按分片号排序的Parts
+func (u *Uploader) uploadParts(c context.Context, ossKey string, uploadId string, r io.Reader, first []byte, partSize int64) (Parts, error) {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		parts    Parts
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	chunks := make(chan *uploadChunk)
+	frees := make(chan []byte, u.concurrency) // 回收分片缓存
+	for i := 0; i < u.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				if ctx.Err() == nil {
+					etag, err := u.ossi.UploadPart(ctx, ossKey, uploadId, chunk.partNumber, chunk.data)
+					if err != nil {
+						fail(err)
+					} else {
+						mu.Lock()
+						parts = append(parts, &Part{PartNumber: chunk.partNumber, ETag: etag})
+						mu.Unlock()
+					}
+				}
+				select {
+				case frees <- chunk.data[:cap(chunk.data)]:
+				default:
+				}
+			}
+		}()
+	}
+
+	data, partNumber := first, 1
+loop:
+	for {
+		select {
+		case chunks <- &uploadChunk{partNumber: partNumber, data: data}:
+		case <-ctx.Done():
+			break loop
+		}
+		if int64(len(data)) < partSize {
+			break // 最后一个分片
+		}
+		if partNumber++; partNumber > maxUploaderParts {
+			fail(ErrTooManyParts)
+			break
+		}
+		select {
+		case data = <-frees:
+		default:
+			data = make([]byte, partSize)
+		}
+		n, err := io.ReadFull(r, data)
+		if err == io.EOF {
+			break
+		} else if err == io.ErrUnexpectedEOF {
+			data = data[:n]
+		} else if err != nil {
+			fail(err)
+			break
+		}
+	}
+	close(chunks)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	sort.Sort(parts)
+	return parts, nil
+}
+
+/*=================================*\
+	服务端复制
+\*=================================*/
+
+/*
+Copy 服务端复制对象, 超过5G时使用UploadPartCopy并发复制分片
+*/
+func (u *Uploader) Copy(c context.Context, srcKey string, dstKey string, opts ...*CopyOptions) error {
+	// 指定源版本时, 大小及元数据都取自该版本
+	opt := firstOption(opts)
+	src := new(ObjectOptions)
+	if opt != nil {
+		src.VersionId = opt.SourceVersionId
+	}
+	info, err := u.ossi.HeadObject(c, srcKey, src)
+	if err != nil {
+		return err
+	}
+	if info.ContentLength <= maxCopyObjectSize {
+		_, err = u.ossi.CopyObject(c, srcKey, dstKey, opts...)
+		return err
+	}
+
+	// 分片复制不会复制元数据, COPY时沿用源对象的元数据
+	put := &PutOptions{ContentType: info.ContentType, Metadata: info.Metadata}
+	if opt != nil && opt.MetadataDirective == MetadataDirectiveReplace {
+		put = &opt.PutOptions
+	} else if opt != nil {
+		put.Tags = opt.Tags
+	}
+	uploadId, err := u.ossi.InitiateMultipartUpload(c, dstKey, put)
+	if err != nil {
+		return err
+	}
+
+	parts, err := u.copyParts(c, srcKey, src, dstKey, uploadId, info.ContentLength)
+	if err == nil {
+		err = u.ossi.CompleteMultipartUpload(c, dstKey, uploadId, parts)
+	}
+	if err != nil {
+		u.ossi.AbortMultipartUpload(context.WithoutCancel(c), dstKey, uploadId)
+		return err
+	}
+	return nil
+}
+
+// copyParts 并发复制源对象的全部分片, 返回按分片号排序的Parts
+func (u *Uploader) copyParts(c context.Context, srcKey string, src *ObjectOptions, dstKey string, uploadId string, size int64) (Parts, error) {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	partSize := u.partSize
+	if size > partSize*maxUploaderParts {
+		partSize = (size + maxUploaderParts - 1) / maxUploaderParts
+	}
+	count := int((size + partSize - 1) / partSize)
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	parts := make(Parts, count)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	numbers := make(chan int)
+	for i := 0; i < u.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := range numbers {
+				start := int64(n-1) * partSize
+				end := start + partSize - 1
+				if end >= size {
+					end = size - 1
+				}
+				etag, err := u.ossi.UploadPartCopy(ctx, srcKey, dstKey, uploadId, n, &Range{Start: uint64(start), End: uint64(end)}, src)
+				if err != nil {
+					fail(err)
+					continue
+				}
+				parts[n-1] = &Part{PartNumber: n, ETag: etag}
+			}
+		}()
+	}
+
+loop:
+	for n := 1; n <= count; n++ {
+		select {
+		case numbers <- n:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return parts, nil
+}
+
+/*=================================*\
+	断点续传
+\*=================================*/
+
+// uploadCheckpoint 断点续传记录, 保存于本地JSON文件
+type uploadCheckpoint struct {
+	Key      string    `json:"key"`
+	UploadId string    `json:"upload_id"`
+	FilePath string    `json:"file_path"`
+	FileSize int64     `json:"file_size"`
+	ModTime  time.Time `json:"mod_time"`
+	PartSize int64     `json:"part_size"`
+	Parts    Parts     `json:"parts"`
+}
+
+/*
+UploadFile 断点续传本地文件. 已完成的分片记录在checkpointPath, 中断后再次调用只上传缺失的分片.
+文件大小或修改时间变化时重新上传. 失败时保留分片上传(不abort)以便续传, 成功后删除断点文件.
+*/
+func (u *Uploader) UploadFile(c context.Context, ossKey string, filePath string, checkpointPath string, opts ...*PutOptions) error {
+	f, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() <= u.partSize {
+		if err = u.ossi.PutObject(c, ossKey, fi.Size(), io.NewSectionReader(f, 0, fi.Size()), opts...); err != nil {
+			return err
+		}
+		os.Remove(checkpointPath)
+		return nil
+	}
+
+	cp, err := u.resumeCheckpoint(c, ossKey, filePath, fi, checkpointPath)
+	if err != nil {
+		return err
+	}
+	if cp == nil {
+		cp = &uploadCheckpoint{
+			Key:      ossKey,
+			FilePath: filePath,
+			FileSize: fi.Size(),
+			ModTime:  fi.ModTime(),
+			PartSize: u.partSize,
+		}
+		if cp.FileSize > cp.PartSize*maxUploaderParts {
+			cp.PartSize = (cp.FileSize + maxUploaderParts - 1) / maxUploaderParts
+		}
+		if cp.UploadId, err = u.ossi.InitiateMultipartUpload(c, ossKey, opts...); err != nil {
+			return err
+		}
+		if err = saveCheckpoint(checkpointPath, cp); err != nil {
+			return err
+		}
+	}
+
+	if err = u.uploadMissingParts(c, f, cp, checkpointPath); err != nil {
+		return err
+	}
+	sort.Sort(cp.Parts)
+	if err = u.ossi.CompleteMultipartUpload(c, ossKey, cp.UploadId, cp.Parts); err != nil {
+		return err
+	}
+	os.Remove(checkpointPath)
+	return nil
+}
+
+// resumeCheckpoint 读取并校验断点. 无效时放弃旧的分片上传并返回nil
+func (u *Uploader) resumeCheckpoint(c context.Context, ossKey string, filePath string, fi os.FileInfo, checkpointPath string) (*uploadCheckpoint, error) {
+	cp := new(uploadCheckpoint)
+	if err := loadCheckpoint(checkpointPath, cp); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if cp.Key != ossKey || cp.FilePath != filePath || cp.FileSize != fi.Size() || !cp.ModTime.Equal(fi.ModTime()) {
+		// 源文件已变化, 旧的分片不可再用
+		u.ossi.AbortMultipartUpload(c, cp.Key, cp.UploadId)
+		return nil, nil
+	}
+
+	// 以服务端ListParts为准, 只保留ETag一致的分片
+	uploaded := make(map[int]string)
+	for marker := 0; ; {
+		result, err := u.ossi.ListParts(c, ossKey, cp.UploadId, marker, 0)
+		if err != nil {
+			if IsNotFound(err) {
+				// 分片上传已完成,放弃或过期
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, p := range result.Parts {
+			uploaded[p.PartNumber] = p.ETag
+		}
+		if !result.IsTruncated {
+			break
+		}
+		marker = result.NextPartNumberMarker
+	}
+	parts := cp.Parts[:0]
+	for _, p := range cp.Parts {
+		if etag, ok := uploaded[p.PartNumber]; ok && etag == p.ETag {
+			parts = append(parts, p)
+		}
+	}
+	cp.Parts = parts
+	return cp, nil
+}
+
+// uploadMissingParts 并发上传断点中缺失的分片, 每完成一个分片即保存断点
+func (u *Uploader) uploadMissingParts(c context.Context, f io.ReaderAt, cp *uploadCheckpoint, checkpointPath string) error {
+	ctx, cancel := context.WithCancel(c)
+	defer cancel()
+
+	done := make(map[int]bool, len(cp.Parts))
+	for _, p := range cp.Parts {
+		done[p.PartNumber] = true
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		firstErr error
+	)
+	fail := func(err error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if firstErr == nil {
+			firstErr = err
+			cancel()
+		}
+	}
+
+	numbers := make(chan int)
+	for i := 0; i < u.concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := make([]byte, cp.PartSize)
+			for n := range numbers {
+				offset := int64(n-1) * cp.PartSize
+				size := cp.FileSize - offset
+				if size > cp.PartSize {
+					size = cp.PartSize
+				}
+				if read, err := f.ReadAt(data[:size], offset); int64(read) != size {
+					// 文件在续传期间被截断时分片不完整, 不能上传
+					if err == nil || err == io.EOF {
+						err = io.ErrUnexpectedEOF
+					}
+					fail(err)
+					continue
+				}
+				etag, err := u.ossi.UploadPart(ctx, cp.Key, cp.UploadId, n, data[:size])
+				if err != nil {
+					fail(err)
+					continue
+				}
+				mu.Lock()
+				cp.Parts = append(cp.Parts, &Part{PartNumber: n, ETag: etag})
+				err = saveCheckpoint(checkpointPath, cp)
+				mu.Unlock()
+				if err != nil {
+					fail(err)
+				}
+			}
+		}()
+	}
+
+	count := int((cp.FileSize + cp.PartSize - 1) / cp.PartSize)
+loop:
+	for n := 1; n <= count; n++ {
+		if done[n] {
+			continue
+		}
+		select {
+		case numbers <- n:
+		case <-ctx.Done():
+			break loop
+		}
+	}
+	close(numbers)
+	wg.Wait()
+
+	if firstErr != nil {
+		return firstErr
+	}
+	return c.Err()
+}
@@ -0,0 +1,188 @@
+package oss
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+// multipartServer 模拟分片上传的服务端
+type multipartServer struct {
+	mu       sync.Mutex
+	parts    map[int][]byte
+	object   []byte
+	aborted  bool
+	uploaded int // UploadPart请求次数
+	failPart int // 该分片返回403
+}
+
+func (s *multipartServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	q := r.URL.Query()
+	switch {
+	case r.Method == http.MethodPost && q.Has("uploads"):
+		s.parts = make(map[int][]byte)
+		io.WriteString(w, "<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>")
+	case r.Method == http.MethodPut && q.Has("partNumber"):
+		n, _ := strconv.Atoi(q.Get("partNumber"))
+		if n == s.failPart {
+			io.Copy(io.Discard, r.Body)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		s.uploaded++
+		s.parts[n], _ = io.ReadAll(r.Body)
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, n))
+	case r.Method == http.MethodPost && q.Has("uploadId"):
+		var complete completeMultipartUpload
+		xml.NewDecoder(r.Body).Decode(&complete)
+		for _, p := range complete.Parts {
+			s.object = append(s.object, s.parts[p.PartNumber]...)
+		}
+	case r.Method == http.MethodGet && q.Has("uploadId"):
+		result := ListPartsResult{UploadId: q.Get("uploadId")}
+		for n := range s.parts {
+			result.Parts = append(result.Parts, &PartInfo{Part: Part{PartNumber: n, ETag: fmt.Sprintf(`"%d"`, n)}})
+		}
+		xml.NewEncoder(w).Encode(&result)
+	case r.Method == http.MethodDelete && q.Has("uploadId"):
+		s.aborted = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPut:
+		s.object, _ = io.ReadAll(r.Body)
+	default:
+		w.WriteHeader(http.StatusNotImplemented)
+	}
+}
+
+func TestUploaderUpload(t *testing.T) {
+	for _, size := range []int{0, minUploaderPartSize - 1, 3*minUploaderPartSize + 7} {
+		ms := new(multipartServer)
+		srv := httptest.NewTLSServer(ms)
+
+		data := make([]byte, size)
+		for i := range data {
+			data[i] = byte(i)
+		}
+		u := NewUploader(newTestOSSI(srv), &UploaderConfig{PartSize: minUploaderPartSize, Concurrency: 2})
+		if err := u.Upload(ctx, ossKey, bytes.NewReader(data)); err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(ms.object, data) || ms.aborted {
+			t.Fatalf("size %d: uploaded %d bytes, aborted %v", size, len(ms.object), ms.aborted)
+		}
+		srv.Close()
+	}
+}
+
+func TestUploaderUploadFile(t *testing.T) {
+	ms := &multipartServer{failPart: 3}
+	srv := httptest.NewTLSServer(ms)
+	defer srv.Close()
+
+	dir := t.TempDir()
+	filePath := filepath.Join(dir, "archive")
+	checkpointPath := filepath.Join(dir, "archive.cp")
+	data := make([]byte, 4*minUploaderPartSize+7)
+	for i := range data {
+		data[i] = byte(i)
+	}
+	if err := os.WriteFile(filePath, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	u := NewUploader(newTestOSSI(srv), &UploaderConfig{PartSize: minUploaderPartSize, Concurrency: 1})
+	if err := u.UploadFile(ctx, ossKey, filePath, checkpointPath); !IsAccessDenied(err) {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(checkpointPath); err != nil {
+		t.Fatal(err)
+	}
+
+	// 续传只上传缺失的分片
+	ms.failPart, ms.uploaded = 0, 0
+	if err := u.UploadFile(ctx, ossKey, filePath, checkpointPath); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(ms.object, data) || ms.uploaded != 3 {
+		t.Fatalf("uploaded %d bytes in %d parts", len(ms.object), ms.uploaded)
+	}
+	if _, err := os.Stat(checkpointPath); !os.IsNotExist(err) {
+		t.Fatal(err)
+	}
+}
+
+func TestUploaderCopyVersion(t *testing.T) {
+	const size = maxCopyObjectSize + 1
+	var (
+		mu     sync.Mutex
+		copied int64
+	)
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case r.Method == http.MethodHead:
+			// 当前版本很小, 只有指定的历史版本超过5G
+			if q.Get("versionId") == "v1" {
+				w.Header().Set("Content-Length", strconv.FormatInt(size, 10))
+			} else {
+				w.Header().Set("Content-Length", "1")
+			}
+		case r.Method == http.MethodPost && q.Has("uploads"):
+			io.WriteString(w, "<InitiateMultipartUploadResult><UploadId>1</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == http.MethodPut && q.Has("partNumber"):
+			if r.Header.Get("x-amz-copy-source") != "/test/"+ossKey+"?versionId=v1" {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			var start, end int64
+			fmt.Sscanf(r.Header.Get("x-amz-copy-source-range"), "bytes=%d-%d", &start, &end)
+			mu.Lock()
+			copied += end - start + 1
+			mu.Unlock()
+			io.WriteString(w, `<CopyPartResult><ETag>"etag"</ETag></CopyPartResult>`)
+		case r.Method == http.MethodPost && q.Has("uploadId"):
+			io.Copy(io.Discard, r.Body)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer srv.Close()
+
+	u := NewUploader(newTestOSSI(srv), &UploaderConfig{PartSize: 1 << 30, Concurrency: 2})
+	if err := u.Copy(ctx, ossKey, ossKey+"-copy", &CopyOptions{SourceVersionId: "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	if copied != size {
+		t.Fatalf("copied %d bytes", copied)
+	}
+}
+
+func TestUploaderShortRead(t *testing.T) {
+	ms := new(multipartServer)
+	srv := httptest.NewTLSServer(ms)
+	defer srv.Close()
+
+	// 断点记录的文件大小超过实际可读的内容, 最后的分片不完整
+	data := make([]byte, 2*minUploaderPartSize)
+	cp := &uploadCheckpoint{Key: ossKey, UploadId: "1", FileSize: 2*minUploaderPartSize + 7, PartSize: minUploaderPartSize}
+	ms.parts = make(map[int][]byte)
+	u := NewUploader(newTestOSSI(srv), &UploaderConfig{PartSize: minUploaderPartSize, Concurrency: 1})
+	err := u.uploadMissingParts(ctx, bytes.NewReader(data), cp, filepath.Join(t.TempDir(), "short.cp"))
+	if err != io.ErrUnexpectedEOF {
+		t.Fatal(err)
+	}
+	if _, ok := ms.parts[3]; ok {
+		t.Fatal("short part uploaded")
+	}
+}
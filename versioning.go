@@ -0,0 +1,173 @@
+package oss
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"time"
+)
+
+// 桶的多版本状态, 从未开启时为空
+const (
+	VersioningEnabled   = "Enabled"
+	VersioningSuspended = "Suspended"
+)
+
+// VersioningConfiguration 桶的多版本配置(S3的?versioning格式)
+type VersioningConfiguration struct {
+	XMLName xml.Name `xml:"VersioningConfiguration"`
+	Status  string   `xml:"Status,omitempty"`
+}
+
+// EnableBucketVersioning 开启多版本. 开启后覆盖及删除都保留历史版本, 只能暂停不能关闭
+func (b *bucketAdminImpl) EnableBucketVersioning(ctx context.Context, bucket string) error {
+	return b.putBucketVersioning(ctx, bucket, VersioningEnabled)
+}
+
+// SuspendBucketVersioning 暂停多版本, 已有的历史版本保留, 新写入的版本ID为null
+func (b *bucketAdminImpl) SuspendBucketVersioning(ctx context.Context, bucket string) error {
+	return b.putBucketVersioning(ctx, bucket, VersioningSuspended)
+}
+
+func (b *bucketAdminImpl) putBucketVersioning(ctx context.Context, bucket string, status string) error {
+	body, err := xml.Marshal(&VersioningConfiguration{Status: status})
+	if err != nil {
+		return err
+	}
+	return b.putBucketContent(ctx, body, func(s BucketStorage, contentMD5 string) *RequestSetting {
+		return s.PutBucketVersioning(bucket, contentMD5)
+	})
+}
+
+// GetBucketVersioning 返回多版本状态: VersioningEnabled, VersioningSuspended或空(从未开启)
+func (b *bucketAdminImpl) GetBucketVersioning(ctx context.Context, bucket string) (string, error) {
+	rsp, err := b.do(ctx, &request{
+		setting: func(s Storage) *RequestSetting { return s.(BucketStorage).GetBucketVersioning(bucket) },
+	})
+	if err != nil {
+		return "", err
+	}
+	defer discardResponseBody(rsp)
+
+	result := new(VersioningConfiguration)
+	if err = xml.NewDecoder(rsp.Body).Decode(result); err != nil {
+		return "", err
+	}
+	return result.Status, nil
+}
+
+/****************************************
+ * list object versions 辅助数据结构
+ ****************************************/
+
+// ListObjectVersionsResult 列举对象版本结果, Versions按key及新旧顺序包含版本和删除标记
+type ListObjectVersionsResult struct {
+	XMLName             xml.Name         `xml:"ListVersionsResult"`
+	Name                string           `xml:"Name"`
+	Prefix              string           `xml:"Prefix"`
+	Delimiter           string           `xml:"Delimiter"`
+	MaxKeys             int              `xml:"MaxKeys"`
+	IsTruncated         bool             `xml:"IsTruncated"`
+	KeyMarker           string           `xml:"KeyMarker"`
+	VersionIdMarker     string           `xml:"VersionIdMarker"`
+	NextKeyMarker       string           `xml:"NextKeyMarker"`
+	NextVersionIdMarker string           `xml:"NextVersionIdMarker"`
+	CommonPrefixes      []string         `xml:"CommonPrefixes>Prefix"`
+	Versions            []*ObjectVersion `xml:",any"` // Version与DeleteMarker交错返回, 用any保持顺序
+}
+
+// ObjectVersion 对象的一个版本, DeleteMarker为true时是删除标记(没有ETag及Size)
+type ObjectVersion struct {
+	XMLName      xml.Name  // Version或DeleteMarker
+	Key          string    `xml:"Key"`
+	VersionId    string    `xml:"VersionId"`
+	IsLatest     bool      `xml:"IsLatest"`
+	LastModified time.Time `xml:"LastModified"`
+	ETag         string    `xml:"ETag"`
+	Size         int64     `xml:"Size"`
+	StorageClass string    `xml:"StorageClass"`
+	DeleteMarker bool      `xml:"-"`
+}
+
+func ExtractListObjectVersionsResult(rsp *http.Response) (*ListObjectVersionsResult, error) {
+
+	result := new(ListObjectVersionsResult)
+
+	err := xml.NewDecoder(rsp.Body).Decode(result)
+	if err != nil {
+		return nil, err
+	}
+
+	// any会收集未声明的元素, 只保留版本及删除标记
+	versions := result.Versions[:0]
+	for _, v := range result.Versions {
+		switch v.XMLName.Local {
+		case "Version":
+			versions = append(versions, v)
+		case "DeleteMarker":
+			v.DeleteMarker = true
+			versions = append(versions, v)
+		}
+	}
+	result.Versions = versions
+	return result, nil
+}
+
+/****************************************
+ * 对象版本遍历
+ ****************************************/
+
+/*
+ObjectVersionIterator 遍历前缀下的全部对象版本(含删除标记), 自动翻页. 用法:
+
+	it := NewObjectVersionIterator(o, prefix, 0)
+	for it.Next(ctx) {
+		v := it.Version()
+	}
+	err := it.Err()
+*/
+type ObjectVersionIterator struct {
+	ossi    OSSI
+	prefix  string
+	maxKeys int
+
+	keyMarker       string
+	versionIdMarker string
+	versions        []*ObjectVersion
+	current         *ObjectVersion
+	done            bool
+	err             error
+}
+
+// NewObjectVersionIterator maxKeys为每页数量, 0表示服务端默认(1000)
+func NewObjectVersionIterator(o OSSI, prefix string, maxKeys int) *ObjectVersionIterator {
+	return &ObjectVersionIterator{ossi: o, prefix: prefix, maxKeys: maxKeys}
+}
+
+// Next 移动到下一个版本, 遍历结束或出错时返回false
+func (it *ObjectVersionIterator) Next(ctx context.Context) bool {
+	for len(it.versions) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+		result, err := it.ossi.ListObjectVersions(ctx, it.prefix, "", it.keyMarker, it.versionIdMarker, it.maxKeys)
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.versions = result.Versions
+		it.keyMarker, it.versionIdMarker = result.NextKeyMarker, result.NextVersionIdMarker
+		// 避免服务端未返回marker时重复列举
+		it.done = !result.IsTruncated || result.NextKeyMarker == ""
+	}
+	it.current, it.versions = it.versions[0], it.versions[1:]
+	return true
+}
+
+func (it *ObjectVersionIterator) Version() *ObjectVersion {
+	return it.current
+}
+
+func (it *ObjectVersionIterator) Err() error {
+	return it.err
+}